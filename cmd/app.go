@@ -0,0 +1,85 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/Jiei-S/boilerplate-clean-architecture/internal/adapter/controller"
+	bunDB "github.com/Jiei-S/boilerplate-clean-architecture/internal/infrastructure/bun"
+	"github.com/Jiei-S/boilerplate-clean-architecture/internal/infrastructure/config"
+	"github.com/Jiei-S/boilerplate-clean-architecture/internal/infrastructure/health"
+	"github.com/Jiei-S/boilerplate-clean-architecture/internal/infrastructure/lifecycle"
+	"github.com/Jiei-S/boilerplate-clean-architecture/internal/infrastructure/metrics"
+	"github.com/Jiei-S/boilerplate-clean-architecture/internal/infrastructure/ratelimit"
+	"github.com/Jiei-S/boilerplate-clean-architecture/internal/usecase"
+	"github.com/uptrace/bun"
+	"github.com/uptrace/bun/extra/bunotel"
+)
+
+// App is what main needs from the object graph built by Init.
+type App struct {
+	Server    *controller.Server
+	Observe   *controller.ObserveMiddleware
+	Auth      *controller.AuthMiddleware
+	RateLimit *controller.RateLimitMiddleware
+	Tx        *controller.TxMiddleware
+	Readiness *lifecycle.Readiness
+	Limiter   *ratelimit.Limiter
+	// Idempotency sweeps the expired idempotency keys.
+	Idempotency *usecase.Idempotency
+}
+
+// newHealthRegistry checks the database first, so that an instance that
+// lost it stops receiving traffic.
+func newHealthRegistry(cfg config.HealthConfig, db *bun.DB) *health.Registry {
+	registry := health.NewRegistry(cfg)
+	registry.Register("database", db.PingContext)
+	return registry
+}
+
+// newDB connects to the database, reports its connection pool and the
+// duration of its queries to m, and traces its queries.
+func newDB(cfg config.DatabaseConfig, m *metrics.Metrics) (*bun.DB, func(), error) {
+	db, closeDB, err := bunDB.NewDB(cfg)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := m.RegisterDB(db.DB, cfg.Driver); err != nil {
+		closeDB()
+		return nil, nil, err
+	}
+	db.AddQueryHook(m.QueryHook())
+	db.AddQueryHook(bunotel.NewQueryHook(bunotel.WithDBName(cfg.Driver)))
+	return db, closeDB, nil
+}
+
+// newRateLimiter limits requests with the configured store. The shared store
+// is backed by a local stand-in until a shared backend, e.g. Redis, is
+// provided. Limits of operations that do not exist are rejected, as they are
+// most likely misspelled.
+func newRateLimiter(cfg config.RateLimitConfig, operations controller.Operations) (*ratelimit.Limiter, error) {
+	known := map[string]bool{}
+	for _, id := range operations {
+		known[id] = true
+	}
+	var unknown []string
+	for id := range cfg.Operations {
+		if !known[id] {
+			unknown = append(unknown, id)
+		}
+	}
+	if len(unknown) > 0 {
+		sort.Strings(unknown)
+		return nil, fmt.Errorf("rateLimit.operations: unknown operations %v", unknown)
+	}
+
+	var store ratelimit.Store = ratelimit.NewMemoryStore()
+	if cfg.Store == config.RateLimitStoreShared {
+		store = ratelimit.NewSharedStore(ratelimit.NewLocalBackend())
+	}
+	return ratelimit.New(cfg, store), nil
+}
+
+func newIdempotency(cfg config.IdempotencyConfig, repo usecase.IdempotencyRepository) *usecase.Idempotency {
+	return usecase.NewIdempotency(repo, cfg.TTL, cfg.SweepInterval)
+}
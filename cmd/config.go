@@ -0,0 +1,23 @@
+package main
+
+import (
+	"errors"
+	"os"
+
+	"github.com/Jiei-S/boilerplate-clean-architecture/internal/infrastructure/config"
+)
+
+const configUsage = "usage: config print"
+
+// runConfig runs the config subcommand with args. loadErr is the error of
+// loading cfg, which is returned after printing, so that the problems show
+// next to the configuration they are about.
+func runConfig(cfg *config.Config, loadErr error, args []string) error {
+	if len(args) != 1 || args[0] != "print" {
+		return errors.New(configUsage)
+	}
+	if err := cfg.Print(os.Stdout); err != nil {
+		return err
+	}
+	return loadErr
+}
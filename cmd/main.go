@@ -2,76 +2,148 @@ package main
 
 import (
 	"context"
+	"errors"
 	"flag"
 	"fmt"
-	"log"
+	"log/slog"
 	"net/http"
 	"os"
 	"os/signal"
 	"syscall"
 
-	"github.com/caarlos0/env"
-
+	"github.com/Jiei-S/boilerplate-clean-architecture/internal/adapter/controller"
+	"github.com/Jiei-S/boilerplate-clean-architecture/internal/infrastructure/config"
+	"github.com/Jiei-S/boilerplate-clean-architecture/internal/infrastructure/lifecycle"
+	"github.com/Jiei-S/boilerplate-clean-architecture/internal/infrastructure/logging"
 	rest "github.com/Jiei-S/boilerplate-clean-architecture/internal/infrastructure/openapi"
+	"github.com/Jiei-S/boilerplate-clean-architecture/internal/infrastructure/tracing"
 
 	chi_middleware "github.com/deepmap/oapi-codegen/pkg/chi-middleware"
+	"github.com/getkin/kin-openapi/openapi3filter"
 	"github.com/go-chi/chi/v5"
-	"github.com/go-chi/httplog"
+	"github.com/go-chi/chi/v5/middleware"
 )
 
-type ServerEnv struct {
-	Port int `env:"API_PORT"`
-}
-
 func main() {
-	cfg := ServerEnv{}
-	if err := env.Parse(&cfg); err != nil {
-		panic(err)
+	cfg, args, err := config.Load(os.Args[1:])
+	if errors.Is(err, flag.ErrHelp) {
+		return
 	}
 
-	swagger, err := rest.GetSwagger()
+	if len(args) > 0 {
+		switch args[0] {
+		case "config":
+			err = runConfig(cfg, err, args[1:])
+		case "migrate":
+			if err == nil {
+				slog.SetDefault(logging.New(cfg.Log, os.Stdout))
+				err = runMigrate(context.Background(), cfg, args[1:])
+			}
+		case "token":
+			if err == nil {
+				err = cfg.ValidateAuth()
+			}
+			if err == nil {
+				err = runToken(cfg, args[1:])
+			}
+		default:
+			err = fmt.Errorf("unknown command %q", args[0])
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %s\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+	if err == nil {
+		err = cfg.ValidateAuth()
+	}
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Error: %s\n", err)
 		os.Exit(1)
 	}
 
-	swagger.Servers = nil
+	// Logs of components that are not wired, e.g. the lifecycle manager, go
+	// through the default logger.
+	logger := logging.New(cfg.Log, os.Stdout)
+	slog.SetDefault(logger)
 
-	logger := httplog.NewLogger("server", httplog.Options{
-		JSON: true,
-	})
+	if cfg.Database.AutoMigrate && cfg.Database.Driver != config.DriverMemory {
+		if err := autoMigrate(context.Background(), cfg); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %s\n", err)
+			os.Exit(1)
+		}
+	}
 
-	r := chi.NewRouter()
-	c := Init()
-	r.Use(chi_middleware.OapiRequestValidator(swagger))
-	r.Use(httplog.RequestLogger(logger))
-	r.Use(c.Recovery)
-	r.Use(c.SetDBMiddleware)
+	swagger, err := rest.GetSwagger()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %s\n", err)
+		os.Exit(1)
+	}
 
-	rest.HandlerFromMux(c, r)
+	swagger.Servers = nil
 
-	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGTERM, os.Interrupt, os.Kill)
-	defer stop()
+	closeTracing, err := tracing.Setup(cfg.Tracing)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %s\n", err)
+		os.Exit(1)
+	}
 
-	port := flag.Int("port", cfg.Port, "Port for HTTP server")
-	flag.Parse()
+	r := chi.NewRouter()
+	initApp := Init
+	if cfg.Database.Driver == config.DriverMemory {
+		initApp = InitInMemory
+	}
+	app, cleanup, err := initApp(swagger, cfg, logger)
+	if err != nil {
+		closeTracing()
+		fmt.Fprintf(os.Stderr, "Error: %s\n", err)
+		os.Exit(1)
+	}
+	r.Use(controller.RequestID)
+	r.Use(controller.LimitRequestBody(cfg.Server.MaxBodyBytes))
+	if cfg.Server.TrustProxyHeaders {
+		r.Use(middleware.RealIP)
+	}
+	r.Use(app.Observe.ObserveRequests)
+	r.Use(app.RateLimit.LimitAuthFailures)
+	r.Use(app.Auth.Authentication(chi_middleware.OapiRequestValidatorWithOptions(swagger, &chi_middleware.Options{
+		ErrorHandler: controller.RequestValidationError,
+		Options: openapi3filter.Options{
+			AuthenticationFunc: app.Auth.Authenticate,
+		},
+	})))
+	r.Use(app.RateLimit.RateLimit)
+	r.Use(controller.Recovery)
+
+	rest.HandlerWithOptions(app.Server, rest.ChiServerOptions{
+		BaseRouter:       r,
+		Middlewares:      []rest.MiddlewareFunc{app.Tx.SetDBMiddleware},
+		ErrorHandlerFunc: controller.ParamError,
+	})
 
 	s := &http.Server{
 		Handler: r,
-		Addr:    fmt.Sprintf("0.0.0.0:%d", *port),
+		Addr:    fmt.Sprintf("0.0.0.0:%d", cfg.Server.Port),
 	}
-
+	m := lifecycle.NewManager(s, app.Readiness, cfg.Server.DrainDelay, cfg.Server.ShutdownTimeout)
+	// Closers run in reverse order, so that the spans of closing the
+	// database are flushed too.
+	m.AddWorker("rate limiter sweeper", app.Limiter.Run)
+	m.AddWorker("idempotency key sweeper", app.Idempotency.Run)
+	m.AddCloser("tracing", closeTracing)
+	m.AddCloser("database", cleanup)
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGTERM, os.Interrupt)
 	go func() {
+		// A second signal terminates at once instead of waiting for the drain.
 		<-ctx.Done()
-		log.Printf("Shutting down server...")
-
-		ctx, cancel := context.WithTimeout(context.Background(), 10)
-		defer cancel()
-		if err := s.Shutdown(ctx); err != nil {
-			log.Printf("Error: %s\n", err)
-		}
+		stop()
 	}()
 
-	log.Printf("Server listening on %s", s.Addr)
-	log.Fatal(s.ListenAndServe())
+	if err := m.Run(ctx); err != nil {
+		slog.Error("Server failed", "error", err)
+		os.Exit(1)
+	}
+	slog.Info("Server stopped")
 }
@@ -0,0 +1,119 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"github.com/Jiei-S/boilerplate-clean-architecture/internal/infrastructure/bun"
+	"github.com/Jiei-S/boilerplate-clean-architecture/internal/infrastructure/config"
+)
+
+// migrationsDir is where `migrate create` writes, relative to the repository
+// root. The other subcommands run the migrations embedded in the binary.
+const migrationsDir = "internal/infrastructure/bun/migrations"
+
+const migrateUsage = "usage: migrate up | down | status | unlock | create <name>"
+
+// runMigrate runs the migrate subcommand with args, e.g. ["up"].
+func runMigrate(ctx context.Context, cfg *config.Config, args []string) error {
+	if len(args) == 0 {
+		return errors.New(migrateUsage)
+	}
+
+	if args[0] == "create" {
+		if len(args) != 2 {
+			return errors.New(migrateUsage)
+		}
+		files, err := bun.CreateMigration(migrationsDir, args[1])
+		for _, file := range files {
+			fmt.Printf("created %s\n", file)
+		}
+		return err
+	}
+	if len(args) != 1 {
+		return errors.New(migrateUsage)
+	}
+	if cfg.Database.Driver == config.DriverMemory {
+		return errors.New("the memory driver has no migrations")
+	}
+
+	db, closeDB, err := bun.NewDB(cfg.Database)
+	if err != nil {
+		return err
+	}
+	defer closeDB()
+	migrator, err := bun.NewMigrator(db)
+	if err != nil {
+		return err
+	}
+
+	switch args[0] {
+	case "up":
+		group, err := bun.Migrate(ctx, migrator, cfg.Database.MigrationLockTimeout)
+		if err != nil {
+			return err
+		}
+		if group.IsZero() {
+			fmt.Println("no pending migrations")
+			return nil
+		}
+		fmt.Printf("migrated to %s\n", group)
+	case "down":
+		group, err := bun.Rollback(ctx, migrator, cfg.Database.MigrationLockTimeout)
+		if err != nil {
+			return err
+		}
+		if group.IsZero() {
+			fmt.Println("no migrations to roll back")
+			return nil
+		}
+		fmt.Printf("rolled back %s\n", group)
+	case "status":
+		if err := migrator.Init(ctx); err != nil {
+			return err
+		}
+		migrations, err := migrator.MigrationsWithStatus(ctx)
+		if err != nil {
+			return err
+		}
+		for _, m := range migrations {
+			status := "pending"
+			if m.IsApplied() {
+				status = fmt.Sprintf("applied in group %d at %s", m.GroupID, m.MigratedAt.Format("2006-01-02 15:04:05"))
+			}
+			fmt.Printf("%s_%s\t%s\n", m.Name, m.Comment, status)
+		}
+	case "unlock":
+		if err := migrator.Init(ctx); err != nil {
+			return err
+		}
+		return migrator.Unlock(ctx)
+	default:
+		return errors.New(migrateUsage)
+	}
+	return nil
+}
+
+// autoMigrate applies pending migrations before the server starts.
+func autoMigrate(ctx context.Context, cfg *config.Config) error {
+	db, closeDB, err := bun.NewDB(cfg.Database)
+	if err != nil {
+		return err
+	}
+	defer closeDB()
+	migrator, err := bun.NewMigrator(db)
+	if err != nil {
+		return err
+	}
+
+	group, err := bun.Migrate(ctx, migrator, cfg.Database.MigrationLockTimeout)
+	if err != nil {
+		return err
+	}
+	if !group.IsZero() {
+		slog.Info("Migrated", "group", group.String())
+	}
+	return nil
+}
@@ -0,0 +1,38 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Jiei-S/boilerplate-clean-architecture/internal/infrastructure/auth"
+	"github.com/Jiei-S/boilerplate-clean-architecture/internal/infrastructure/config"
+)
+
+// runToken runs the token subcommand, which prints a bearer token signed
+// with the configured HMAC secret.
+func runToken(cfg *config.Config, args []string) error {
+	fs := flag.NewFlagSet("token", flag.ContinueOnError)
+	subject := fs.String("sub", "dev", "Subject of the token")
+	roles := fs.String("roles", "", "Comma-separated roles")
+	scopes := fs.String("scopes", "", "Comma-separated scopes")
+	ttl := fs.Duration("ttl", time.Hour, "Lifetime of the token")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	token, err := auth.Sign(cfg.Auth, *subject, splitList(*roles), splitList(*scopes), *ttl)
+	if err != nil {
+		return err
+	}
+	fmt.Println(token)
+	return nil
+}
+
+func splitList(s string) []string {
+	if s == "" {
+		return nil
+	}
+	return strings.Split(s, ",")
+}
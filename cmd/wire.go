@@ -4,20 +4,80 @@
 package main
 
 import (
+	"log/slog"
+
 	"github.com/Jiei-S/boilerplate-clean-architecture/internal/adapter/controller"
 	"github.com/Jiei-S/boilerplate-clean-architecture/internal/adapter/gateway"
-	"github.com/Jiei-S/boilerplate-clean-architecture/internal/infrastructure/bun"
+	"github.com/Jiei-S/boilerplate-clean-architecture/internal/infrastructure/auth"
+	"github.com/Jiei-S/boilerplate-clean-architecture/internal/infrastructure/config"
+	"github.com/Jiei-S/boilerplate-clean-architecture/internal/infrastructure/health"
+	"github.com/Jiei-S/boilerplate-clean-architecture/internal/infrastructure/lifecycle"
+	"github.com/Jiei-S/boilerplate-clean-architecture/internal/infrastructure/metrics"
+	"github.com/Jiei-S/boilerplate-clean-architecture/internal/infrastructure/ratelimit"
 	"github.com/Jiei-S/boilerplate-clean-architecture/internal/usecase"
 
+	"github.com/getkin/kin-openapi/openapi3"
 	"github.com/google/wire"
 )
 
-func Init() *controller.UserHandler {
+// appSet provides everything but the adapters of the usecase ports.
+var appSet = wire.NewSet(
+	wire.Struct(new(App), "*"),
+	lifecycle.NewReadiness,
+	wire.Bind(new(controller.Readiness), new(*lifecycle.Readiness)),
+	wire.FieldsOf(new(*config.Config), "Health", "Auth", "RateLimit", "Idempotency"),
+	metrics.New,
+	wire.Bind(new(controller.Metrics), new(*metrics.Metrics)),
+	controller.NewOperations,
+	newRateLimiter,
+	wire.Bind(new(controller.RateLimiter), new(*ratelimit.Limiter)),
+	auth.NewVerifier,
+	wire.Bind(new(controller.TokenVerifier), new(*auth.Verifier)),
+	auth.LoadPolicy,
+	controller.NewServer,
+	controller.NewUserHandler,
+	controller.NewAPIKeyHandler,
+	controller.NewHealthHandler,
+	controller.NewMetricsHandler,
+	controller.NewObserveMiddleware,
+	controller.NewAuthMiddleware,
+	controller.NewRateLimitMiddleware,
+	controller.NewTxMiddleware,
+	controller.NewTxPolicy,
+	usecase.NewUserUsecase,
+	usecase.NewAPIKeyUsecase,
+	newIdempotency,
+)
+
+// Init wires the application to the configured database. The returned
+// function closes the database.
+func Init(swagger *openapi3.T, cfg *config.Config, logger *slog.Logger) (*App, func(), error) {
 	wire.Build(
-		controller.NewUserHandler,
-		bun.NewDB,
-		usecase.NewUserUsecase,
+		appSet,
+		wire.FieldsOf(new(*config.Config), "Database"),
+		newDB,
+		newHealthRegistry,
+		wire.Bind(new(controller.HealthChecker), new(*health.Registry)),
 		gateway.NewUserRepository,
+		gateway.NewAPIKeyRepository,
+		gateway.NewIdempotencyRepository,
+		gateway.NewTransactor,
+	)
+	return &App{}, nil, nil
+}
+
+// InitInMemory wires the application to the in-memory adapters, so that it
+// runs without a database and has no dependency to check.
+func InitInMemory(swagger *openapi3.T, cfg *config.Config, logger *slog.Logger) (*App, func(), error) {
+	wire.Build(
+		appSet,
+		health.NewRegistry,
+		wire.Bind(new(controller.HealthChecker), new(*health.Registry)),
+		gateway.NewMemoryStore,
+		gateway.NewMemoryUserRepository,
+		gateway.NewMemoryAPIKeyRepository,
+		gateway.NewMemoryIdempotencyRepository,
+		gateway.NewMemoryTransactor,
 	)
-	return &controller.UserHandler{}
+	return &App{}, nil, nil
 }
@@ -9,16 +9,152 @@ package main
 import (
 	"github.com/Jiei-S/boilerplate-clean-architecture/internal/adapter/controller"
 	"github.com/Jiei-S/boilerplate-clean-architecture/internal/adapter/gateway"
-	"github.com/Jiei-S/boilerplate-clean-architecture/internal/infrastructure/bun"
+	"github.com/Jiei-S/boilerplate-clean-architecture/internal/infrastructure/auth"
+	"github.com/Jiei-S/boilerplate-clean-architecture/internal/infrastructure/config"
+	"github.com/Jiei-S/boilerplate-clean-architecture/internal/infrastructure/health"
+	"github.com/Jiei-S/boilerplate-clean-architecture/internal/infrastructure/lifecycle"
+	"github.com/Jiei-S/boilerplate-clean-architecture/internal/infrastructure/metrics"
+	"github.com/Jiei-S/boilerplate-clean-architecture/internal/infrastructure/ratelimit"
 	"github.com/Jiei-S/boilerplate-clean-architecture/internal/usecase"
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/google/wire"
+	"log/slog"
 )
 
 // Injectors from wire.go:
 
-func Init() *controller.UserHandler {
-	db := bun.NewDB()
-	userRepository := gateway.NewUserRepository()
-	userUsecase := usecase.NewUserUsecase(userRepository)
-	userHandler := controller.NewUserHandler(db, userUsecase)
-	return userHandler
+// Init wires the application to the configured database. The returned
+// function closes the database.
+func Init(swagger *openapi3.T, cfg *config.Config, logger *slog.Logger) (*App, func(), error) {
+	databaseConfig := cfg.Database
+	metricsMetrics, err := metrics.New()
+	if err != nil {
+		return nil, nil, err
+	}
+	db, cleanup, err := newDB(databaseConfig, metricsMetrics)
+	if err != nil {
+		return nil, nil, err
+	}
+	userRepository := gateway.NewUserRepository(db)
+	transactor := gateway.NewTransactor(db, logger)
+	authConfig := cfg.Auth
+	policy, err := auth.LoadPolicy(authConfig)
+	if err != nil {
+		cleanup()
+		return nil, nil, err
+	}
+	idempotencyConfig := cfg.Idempotency
+	idempotencyRepository := gateway.NewIdempotencyRepository(db)
+	idempotency := newIdempotency(idempotencyConfig, idempotencyRepository)
+	userUsecase := usecase.NewUserUsecase(userRepository, transactor, policy, idempotency)
+	userHandler := controller.NewUserHandler(userUsecase)
+	apiKeyRepository := gateway.NewAPIKeyRepository(db)
+	apiKeyUsecase := usecase.NewAPIKeyUsecase(apiKeyRepository, policy)
+	apiKeyHandler := controller.NewAPIKeyHandler(apiKeyUsecase)
+	readiness := lifecycle.NewReadiness()
+	healthConfig := cfg.Health
+	registry := newHealthRegistry(healthConfig, db)
+	healthHandler := controller.NewHealthHandler(readiness, registry, logger)
+	metricsHandler := controller.NewMetricsHandler(metricsMetrics)
+	server := controller.NewServer(userHandler, apiKeyHandler, healthHandler, metricsHandler)
+	operations := controller.NewOperations(swagger)
+	observeMiddleware := controller.NewObserveMiddleware(metricsMetrics, operations, logger)
+	verifier, err := auth.NewVerifier(authConfig)
+	if err != nil {
+		cleanup()
+		return nil, nil, err
+	}
+	authMiddleware := controller.NewAuthMiddleware(verifier, apiKeyUsecase)
+	rateLimitConfig := cfg.RateLimit
+	limiter, err := newRateLimiter(rateLimitConfig, operations)
+	if err != nil {
+		cleanup()
+		return nil, nil, err
+	}
+	rateLimitMiddleware := controller.NewRateLimitMiddleware(limiter, operations, logger)
+	txPolicy, err := controller.NewTxPolicy(swagger)
+	if err != nil {
+		cleanup()
+		return nil, nil, err
+	}
+	txMiddleware := controller.NewTxMiddleware(transactor, txPolicy)
+	app := &App{
+		Server:      server,
+		Observe:     observeMiddleware,
+		Auth:        authMiddleware,
+		RateLimit:   rateLimitMiddleware,
+		Tx:          txMiddleware,
+		Readiness:   readiness,
+		Limiter:     limiter,
+		Idempotency: idempotency,
+	}
+	return app, func() {
+		cleanup()
+	}, nil
 }
+
+// InitInMemory wires the application to the in-memory adapters, so that it
+// runs without a database and has no dependency to check.
+func InitInMemory(swagger *openapi3.T, cfg *config.Config, logger *slog.Logger) (*App, func(), error) {
+	memoryStore := gateway.NewMemoryStore()
+	userRepository := gateway.NewMemoryUserRepository(memoryStore)
+	transactor := gateway.NewMemoryTransactor(memoryStore)
+	authConfig := cfg.Auth
+	policy, err := auth.LoadPolicy(authConfig)
+	if err != nil {
+		return nil, nil, err
+	}
+	idempotencyConfig := cfg.Idempotency
+	idempotencyRepository := gateway.NewMemoryIdempotencyRepository(memoryStore)
+	idempotency := newIdempotency(idempotencyConfig, idempotencyRepository)
+	userUsecase := usecase.NewUserUsecase(userRepository, transactor, policy, idempotency)
+	userHandler := controller.NewUserHandler(userUsecase)
+	apiKeyRepository := gateway.NewMemoryAPIKeyRepository(memoryStore)
+	apiKeyUsecase := usecase.NewAPIKeyUsecase(apiKeyRepository, policy)
+	apiKeyHandler := controller.NewAPIKeyHandler(apiKeyUsecase)
+	readiness := lifecycle.NewReadiness()
+	healthConfig := cfg.Health
+	registry := health.NewRegistry(healthConfig)
+	healthHandler := controller.NewHealthHandler(readiness, registry, logger)
+	metricsMetrics, err := metrics.New()
+	if err != nil {
+		return nil, nil, err
+	}
+	metricsHandler := controller.NewMetricsHandler(metricsMetrics)
+	server := controller.NewServer(userHandler, apiKeyHandler, healthHandler, metricsHandler)
+	operations := controller.NewOperations(swagger)
+	observeMiddleware := controller.NewObserveMiddleware(metricsMetrics, operations, logger)
+	verifier, err := auth.NewVerifier(authConfig)
+	if err != nil {
+		return nil, nil, err
+	}
+	authMiddleware := controller.NewAuthMiddleware(verifier, apiKeyUsecase)
+	rateLimitConfig := cfg.RateLimit
+	limiter, err := newRateLimiter(rateLimitConfig, operations)
+	if err != nil {
+		return nil, nil, err
+	}
+	rateLimitMiddleware := controller.NewRateLimitMiddleware(limiter, operations, logger)
+	txPolicy, err := controller.NewTxPolicy(swagger)
+	if err != nil {
+		return nil, nil, err
+	}
+	txMiddleware := controller.NewTxMiddleware(transactor, txPolicy)
+	app := &App{
+		Server:      server,
+		Observe:     observeMiddleware,
+		Auth:        authMiddleware,
+		RateLimit:   rateLimitMiddleware,
+		Tx:          txMiddleware,
+		Readiness:   readiness,
+		Limiter:     limiter,
+		Idempotency: idempotency,
+	}
+	return app, func() {
+	}, nil
+}
+
+// wire.go:
+
+// appSet provides everything but the adapters of the usecase ports.
+var appSet = wire.NewSet(wire.Struct(new(App), "*"), lifecycle.NewReadiness, wire.Bind(new(controller.Readiness), new(*lifecycle.Readiness)), wire.FieldsOf(new(*config.Config), "Health", "Auth", "RateLimit", "Idempotency"), metrics.New, wire.Bind(new(controller.Metrics), new(*metrics.Metrics)), controller.NewOperations, newRateLimiter, wire.Bind(new(controller.RateLimiter), new(*ratelimit.Limiter)), auth.NewVerifier, wire.Bind(new(controller.TokenVerifier), new(*auth.Verifier)), auth.LoadPolicy, controller.NewServer, controller.NewUserHandler, controller.NewAPIKeyHandler, controller.NewHealthHandler, controller.NewMetricsHandler, controller.NewObserveMiddleware, controller.NewAuthMiddleware, controller.NewRateLimitMiddleware, controller.NewTxMiddleware, controller.NewTxPolicy, usecase.NewUserUsecase, usecase.NewAPIKeyUsecase, newIdempotency)
@@ -0,0 +1,55 @@
+package controller
+
+import (
+	"net/http"
+
+	usecase "github.com/Jiei-S/boilerplate-clean-architecture/internal/usecase"
+)
+
+// APIKeyHandler serves the API key endpoints.
+type APIKeyHandler struct {
+	apiKeys usecase.APIKeyUsecase
+}
+
+func (h *APIKeyHandler) CreateApiKey(w http.ResponseWriter, r *http.Request) {
+	req, err := ToAPIKeyDTO(r.Body)
+	if err != nil {
+		HttpError(w, r, err)
+		return
+	}
+
+	result, err := h.apiKeys.CreateAPIKey(r.Context(), req)
+	if err != nil {
+		HttpError(w, r, err)
+		return
+	}
+
+	// The response holds the key in the clear, which must not be cached.
+	w.Header().Set("Cache-Control", "no-store")
+	handleOK(w, FromCreatedAPIKeyDTO(result))
+}
+
+func (h *APIKeyHandler) ListApiKeys(w http.ResponseWriter, r *http.Request) {
+	result, err := h.apiKeys.ListAPIKeys(r.Context())
+	if err != nil {
+		HttpError(w, r, err)
+		return
+	}
+
+	handleOK(w, FromAPIKeyListDTO(result))
+}
+
+func (h *APIKeyHandler) RevokeApiKey(w http.ResponseWriter, r *http.Request, id string) {
+	if err := h.apiKeys.RevokeAPIKey(r.Context(), id); err != nil {
+		HttpError(w, r, err)
+		return
+	}
+
+	handleNoContent(w)
+}
+
+func NewAPIKeyHandler(apiKeys usecase.APIKeyUsecase) *APIKeyHandler {
+	return &APIKeyHandler{
+		apiKeys: apiKeys,
+	}
+}
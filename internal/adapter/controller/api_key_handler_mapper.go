@@ -0,0 +1,64 @@
+package controller
+
+import (
+	"encoding/json"
+	"io"
+
+	rest "github.com/Jiei-S/boilerplate-clean-architecture/internal/infrastructure/openapi"
+	usecase "github.com/Jiei-S/boilerplate-clean-architecture/internal/usecase"
+	pkgErr "github.com/Jiei-S/boilerplate-clean-architecture/pkg/error"
+)
+
+func ToAPIKeyDTO(
+	body io.ReadCloser,
+) (*usecase.APIKeyInput, *pkgErr.ApplicationError) {
+	var dto rest.CreateApiKeyJSONRequestBody
+	if err := json.NewDecoder(body).Decode(&dto); err != nil {
+		return nil, pkgErr.Wrap(err, "failed to decode request body", pkgErr.LevelWarn, pkgErr.CodeBadRequest)
+	}
+	return &usecase.APIKeyInput{
+		Name:   dto.Name,
+		Scopes: dto.Scopes,
+	}, nil
+}
+
+func FromAPIKeyDTO(
+	dto *usecase.APIKey,
+) *rest.ApiKey {
+	return &rest.ApiKey{
+		Id:         dto.ID,
+		Name:       dto.Name,
+		Prefix:     dto.Prefix,
+		Scopes:     dto.Scopes,
+		CreatedBy:  dto.CreatedBy,
+		CreatedAt:  dto.CreatedAt,
+		LastUsedAt: dto.LastUsedAt,
+		RevokedAt:  dto.RevokedAt,
+	}
+}
+
+func FromCreatedAPIKeyDTO(
+	dto *usecase.CreatedAPIKey,
+) *rest.CreatedApiKey {
+	return &rest.CreatedApiKey{
+		Id:        dto.ID,
+		Name:      dto.Name,
+		Prefix:    dto.Prefix,
+		Scopes:    dto.Scopes,
+		CreatedBy: dto.CreatedBy,
+		CreatedAt: dto.CreatedAt,
+		Key:       dto.Key,
+	}
+}
+
+func FromAPIKeyListDTO(
+	dtos []*usecase.APIKey,
+) *rest.ApiKeyList {
+	list := &rest.ApiKeyList{
+		Items: make([]rest.ApiKey, 0, len(dtos)),
+	}
+	for _, dto := range dtos {
+		list.Items = append(list.Items, *FromAPIKeyDTO(dto))
+	}
+	return list
+}
@@ -0,0 +1,137 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	usecase "github.com/Jiei-S/boilerplate-clean-architecture/internal/usecase"
+	pkgErr "github.com/Jiei-S/boilerplate-clean-architecture/pkg/error"
+	"github.com/getkin/kin-openapi/openapi3filter"
+)
+
+// bearerChallenge is the WWW-Authenticate header of 401 responses.
+const bearerChallenge = `Bearer realm="api"`
+
+var (
+	errMissingToken  = errors.New("missing bearer token")
+	errMissingAPIKey = errors.New("missing API key")
+)
+
+// TokenVerifier returns the principal asserted by a bearer token.
+type TokenVerifier interface {
+	Verify(ctx context.Context, token string) (*usecase.Principal, error)
+}
+
+// AuthMiddleware authenticates requests by their bearer token or API key.
+type AuthMiddleware struct {
+	verifier TokenVerifier
+	apiKeys  usecase.APIKeyUsecase
+}
+
+func NewAuthMiddleware(verifier TokenVerifier, apiKeys usecase.APIKeyUsecase) *AuthMiddleware {
+	return &AuthMiddleware{
+		verifier: verifier,
+		apiKeys:  apiKeys,
+	}
+}
+
+// principalSlot receives the principal authenticated by the request
+// validator, which cannot change the context of the request it validates.
+type principalSlot struct {
+	principal *usecase.Principal
+}
+
+type principalSlotKey struct{}
+
+// Authenticate is the authentication function of the OpenAPI request
+// validator. It is called for each security scheme that the operation
+// accepts, until one succeeds, and verifies either the bearer token or the
+// API key of the request. Its errors are reported to the client, so they
+// must not leak anything about the keys.
+func (m *AuthMiddleware) Authenticate(ctx context.Context, input *openapi3filter.AuthenticationInput) error {
+	var (
+		principal *usecase.Principal
+		err       error
+	)
+	scheme := input.SecurityScheme
+	switch {
+	case scheme == nil:
+		err = fmt.Errorf("unknown security scheme %q", input.SecuritySchemeName)
+	case scheme.Type == "http" && strings.EqualFold(scheme.Scheme, "bearer"):
+		principal, err = m.authenticateBearer(ctx, input.RequestValidationInput.Request)
+	case scheme.Type == "apiKey" && scheme.In == "header":
+		principal, err = m.authenticateAPIKey(ctx, input.RequestValidationInput.Request.Header.Get(scheme.Name))
+	default:
+		err = fmt.Errorf("unsupported security scheme %q", input.SecuritySchemeName)
+	}
+	if err != nil {
+		return err
+	}
+	for _, scope := range input.Scopes {
+		if !hasScope(principal, scope) {
+			return fmt.Errorf("credentials lack scope %q", scope)
+		}
+	}
+
+	if slot, ok := ctx.Value(principalSlotKey{}).(*principalSlot); ok {
+		slot.principal = principal
+	}
+	return nil
+}
+
+func (m *AuthMiddleware) authenticateBearer(ctx context.Context, r *http.Request) (*usecase.Principal, error) {
+	kind, token, ok := strings.Cut(r.Header.Get("Authorization"), " ")
+	if !ok || !strings.EqualFold(kind, "bearer") || token == "" {
+		return nil, errMissingToken
+	}
+	principal, err := m.verifier.Verify(ctx, strings.TrimSpace(token))
+	if err != nil {
+		return nil, fmt.Errorf("invalid bearer token: %w", err)
+	}
+	return principal, nil
+}
+
+func (m *AuthMiddleware) authenticateAPIKey(ctx context.Context, key string) (*usecase.Principal, error) {
+	if key == "" {
+		return nil, errMissingAPIKey
+	}
+	principal, err := m.apiKeys.AuthenticateAPIKey(ctx, key)
+	if err != nil {
+		// Do not tell clients about failures of the database.
+		if err.Code() != pkgErr.CodeUnauthorized {
+			return nil, errors.New("API key could not be verified")
+		}
+		return nil, errors.New(err.Message())
+	}
+	return principal, nil
+}
+
+// Authentication wraps the OpenAPI request validator, whose authentication
+// function is Authenticate, so that the principal it verifies is put in the
+// context of the request for the usecases.
+func (m *AuthMiddleware) Authentication(validator func(http.Handler) http.Handler) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		validated := validator(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if slot, ok := r.Context().Value(principalSlotKey{}).(*principalSlot); ok && slot.principal != nil {
+				r = r.WithContext(usecase.WithPrincipal(r.Context(), slot.principal))
+			}
+			next.ServeHTTP(w, r)
+		}))
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx := context.WithValue(r.Context(), principalSlotKey{}, &principalSlot{})
+			validated.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+func hasScope(p *usecase.Principal, scope string) bool {
+	for _, s := range p.Scopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,26 @@
+package controller
+
+import (
+	"fmt"
+	"net/http"
+
+	pkgErr "github.com/Jiei-S/boilerplate-clean-architecture/pkg/error"
+)
+
+// LimitRequestBody refuses request bodies larger than limit bytes with 413.
+// Bodies that declare their length are refused before they are read; others
+// fail once a reader, such as the request validator or SetDBMiddleware, gets
+// past the limit. It must wrap every middleware that reads bodies.
+func LimitRequestBody(limit int64) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.ContentLength > limit {
+				HttpError(w, r, pkgErr.NewApplicationError(fmt.Sprintf("request body exceeds %d bytes", limit), pkgErr.LevelWarn, pkgErr.CodeRequestTooLarge).
+					WithDetail("maxBytes", limit))
+				return
+			}
+			r.Body = http.MaxBytesReader(w, r.Body, limit)
+			next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -0,0 +1,37 @@
+package controller
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	pkgErr "github.com/Jiei-S/boilerplate-clean-architecture/pkg/error"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLimitRequestBody(t *testing.T) {
+	handler := LimitRequestBody(8)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if _, err := io.ReadAll(r.Body); err != nil {
+			HttpError(w, r, pkgErr.Wrap(err, "request body too large", pkgErr.LevelWarn, pkgErr.CodeRequestTooLarge))
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	send := func(body string, chunked bool) int {
+		r := httptest.NewRequest(http.MethodPost, "/users", strings.NewReader(body))
+		if chunked {
+			r.ContentLength = -1
+		}
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, r)
+		return w.Code
+	}
+
+	assert.Equal(t, http.StatusOK, send("12345678", false))
+	assert.Equal(t, http.StatusRequestEntityTooLarge, send("123456789", false))
+	// Bodies of unknown length fail once they are read past the limit.
+	assert.Equal(t, http.StatusOK, send("12345678", true))
+	assert.Equal(t, http.StatusRequestEntityTooLarge, send("123456789", true))
+}
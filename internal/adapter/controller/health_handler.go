@@ -0,0 +1,119 @@
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/Jiei-S/boilerplate-clean-architecture/internal/infrastructure/health"
+	rest "github.com/Jiei-S/boilerplate-clean-architecture/internal/infrastructure/openapi"
+)
+
+// Readiness reports whether the instance should receive traffic.
+type Readiness interface {
+	Ready() bool
+}
+
+// HealthChecker probes the dependencies of the service.
+type HealthChecker interface {
+	Check(ctx context.Context) []health.Result
+}
+
+// HealthHandler serves the probes of the instance and its dependencies.
+type HealthHandler struct {
+	readiness Readiness
+	health    HealthChecker
+	logger    *slog.Logger
+}
+
+// Livez reports that the process serves requests. It does not look at the
+// dependencies, so that an orchestrator does not restart the instance when
+// only the database is down.
+func (h *HealthHandler) Livez(w http.ResponseWriter, r *http.Request) {
+	handleOK(w, rest.Health{
+		Status: rest.Healthy,
+	})
+}
+
+// Readyz reports whether the instance should receive traffic: it must not
+// be starting or shutting down, and every dependency must be healthy.
+func (h *HealthHandler) Readyz(w http.ResponseWriter, r *http.Request) {
+	readiness := h.checkReadiness(r.Context())
+	if readiness.Status != rest.Healthy {
+		handleServiceUnavailable(w, readiness)
+		return
+	}
+
+	handleOK(w, readiness)
+}
+
+// Health reports the overall status of Readyz.
+func (h *HealthHandler) Health(w http.ResponseWriter, r *http.Request) {
+	status := h.checkReadiness(r.Context()).Status
+	if status != rest.Healthy {
+		handleServiceUnavailable(w, rest.Health{
+			Status: status,
+		})
+		return
+	}
+
+	handleOK(w, rest.Health{
+		Status: status,
+	})
+}
+
+func (h *HealthHandler) checkReadiness(ctx context.Context) rest.Readiness {
+	start := time.Now()
+	server := rest.ComponentHealth{
+		Name:      "server",
+		Status:    rest.Healthy,
+		CheckedAt: start,
+	}
+	if !h.readiness.Ready() {
+		server.Status = rest.Unhealthy
+		server.Error = strPtr("starting or shutting down")
+	}
+
+	readiness := rest.Readiness{
+		Status:     server.Status,
+		Components: []rest.ComponentHealth{server},
+	}
+	for _, result := range h.health.Check(ctx) {
+		component := rest.ComponentHealth{
+			Name:      result.Name,
+			Status:    rest.Healthy,
+			LatencyMs: float64(result.Latency) / float64(time.Millisecond),
+			CheckedAt: result.CheckedAt,
+		}
+		if !result.Healthy() {
+			// Errors of dependencies tell hosts and ports, which probes
+			// that anyone may send must not see.
+			h.logger.WarnContext(ctx, "Dependency unhealthy", "component", result.Name, "error", result.Err)
+			component.Status = rest.Unhealthy
+			component.Error = strPtr("check failed")
+			readiness.Status = rest.Unhealthy
+		}
+		readiness.Components = append(readiness.Components, component)
+	}
+	return readiness
+}
+
+func handleServiceUnavailable(w http.ResponseWriter, obj interface{}) {
+	setHeaderContentType(w)
+	w.WriteHeader(http.StatusServiceUnavailable)
+	json.NewEncoder(w).Encode(obj)
+}
+
+func strPtr(s string) *string {
+	return &s
+}
+
+func NewHealthHandler(readiness Readiness, health HealthChecker, logger *slog.Logger) *HealthHandler {
+	return &HealthHandler{
+		readiness: readiness,
+		health:    health,
+		logger:    logger,
+	}
+}
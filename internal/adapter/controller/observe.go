@@ -0,0 +1,244 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"time"
+	"unicode"
+
+	"github.com/Jiei-S/boilerplate-clean-architecture/internal/infrastructure/logging"
+	pkgErr "github.com/Jiei-S/boilerplate-clean-architecture/pkg/error"
+	"github.com/Jiei-S/boilerplate-clean-architecture/pkg/tracing"
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/go-chi/chi/v5"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	semconv "go.opentelemetry.io/otel/semconv/v1.21.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// unmatchedOperation labels requests that did not match any operation.
+const unmatchedOperation = "unmatched"
+
+// Metrics records the outcome of each request and serves what it recorded.
+type Metrics interface {
+	http.Handler
+	ObserveRequest(operation, method string, status int, duration time.Duration)
+	ObserveError(err *pkgErr.ApplicationError)
+}
+
+// Operations maps each route, keyed by "METHOD /path/{pattern}", to the
+// operationId of its operation in api/openapi.yaml.
+type Operations map[string]string
+
+// Of returns the operationId of the route of r. A request that a middleware
+// rejected before routing is matched against the routes here.
+func (o Operations) Of(r *http.Request) string {
+	rctx := chi.RouteContext(r.Context())
+	if rctx == nil {
+		return unmatchedOperation
+	}
+	pattern := rctx.RoutePattern()
+	if pattern == "" && rctx.Routes != nil {
+		match := chi.NewRouteContext()
+		if rctx.Routes.Match(match, r.Method, r.URL.Path) {
+			pattern = match.RoutePattern()
+		}
+	}
+	if id, ok := o[r.Method+" "+pattern]; ok {
+		return id
+	}
+	return unmatchedOperation
+}
+
+func NewOperations(swagger *openapi3.T) Operations {
+	operations := Operations{}
+	for path, item := range swagger.Paths {
+		for method, op := range item.Operations() {
+			// The spec embedded by oapi-codegen has its operationIds
+			// capitalized, unlike api/openapi.yaml.
+			id := []rune(op.OperationID)
+			if len(id) > 0 {
+				id[0] = unicode.ToLower(id[0])
+			}
+			operations[method+" "+path] = string(id)
+		}
+	}
+	return operations
+}
+
+// MetricsHandler serves the metrics that ObserveMiddleware records.
+type MetricsHandler struct {
+	metrics Metrics
+}
+
+func (h *MetricsHandler) Metrics(w http.ResponseWriter, r *http.Request) {
+	h.metrics.ServeHTTP(w, r)
+}
+
+func NewMetricsHandler(metrics Metrics) *MetricsHandler {
+	return &MetricsHandler{
+		metrics: metrics,
+	}
+}
+
+// ObserveMiddleware records, traces and logs requests.
+type ObserveMiddleware struct {
+	metrics    Metrics
+	operations Operations
+	logger     *slog.Logger
+}
+
+func NewObserveMiddleware(metrics Metrics, operations Operations, logger *slog.Logger) *ObserveMiddleware {
+	return &ObserveMiddleware{
+		metrics:    metrics,
+		operations: operations,
+		logger:     logger,
+	}
+}
+
+// ObserveRequests records the rate, errors and duration of requests by
+// operation, runs each request in a server span named after its operation,
+// continuing the trace of the traceparent header if any, and logs its
+// outcome. It must wrap every middleware but RequestID, so that the
+// requests they reject are observed too.
+func (m *ObserveMiddleware) ObserveRequests(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		ctx := otel.GetTextMapPropagator().Extract(r.Context(), propagation.HeaderCarrier(r.Header))
+		ctx, span := tracing.Start(ctx, r.Method, trace.WithSpanKind(trace.SpanKindServer))
+		defer span.End()
+
+		ww := &observedResponseWriter{ResponseWriter: w}
+		next.ServeHTTP(ww, r.WithContext(ctx))
+
+		operation := m.operations.Of(r)
+		status := ww.Status()
+		duration := time.Since(start)
+		m.metrics.ObserveRequest(operation, r.Method, status, duration)
+
+		span.SetName(operation)
+		span.SetAttributes(
+			semconv.HTTPMethod(r.Method),
+			semconv.HTTPStatusCode(status),
+			attribute.String("http.request_id", RequestIDFromContext(r.Context())),
+		)
+		if rctx := chi.RouteContext(r.Context()); rctx != nil && rctx.RoutePattern() != "" {
+			span.SetAttributes(semconv.HTTPRoute(rctx.RoutePattern()))
+		}
+		if ww.err != nil {
+			m.metrics.ObserveError(ww.err)
+			tracing.RecordError(span, ww.err)
+		}
+		if status >= http.StatusInternalServerError {
+			span.SetStatus(codes.Error, http.StatusText(status))
+		}
+
+		m.logRequest(ctx, r, operation, status, duration, ww.err)
+	})
+}
+
+var logLevels = map[pkgErr.ErrorLevel]slog.Level{
+	pkgErr.LevelInfo:  slog.LevelInfo,
+	pkgErr.LevelWarn:  slog.LevelWarn,
+	pkgErr.LevelError: slog.LevelError,
+}
+
+// logRequest logs a request that failed with err at the level of err, along
+// with its cause, and any other request at info level.
+func (m *ObserveMiddleware) logRequest(
+	ctx context.Context,
+	r *http.Request,
+	operation string,
+	status int,
+	duration time.Duration,
+	err *pkgErr.ApplicationError,
+) {
+	attrs := []slog.Attr{
+		slog.String("method", r.Method),
+		slog.String("path", logging.RedactURL(r.URL)),
+		slog.String("operation", operation),
+		slog.Int("status", status),
+		slog.Float64("durationMs", float64(duration)/float64(time.Millisecond)),
+	}
+	if err == nil {
+		level := slog.LevelInfo
+		if status >= http.StatusInternalServerError {
+			level = slog.LevelError
+		}
+		m.logger.LogAttrs(ctx, level, "Request completed", attrs...)
+		return
+	}
+
+	level, ok := logLevels[err.Level()]
+	if !ok {
+		level = slog.LevelError
+	}
+	m.logger.LogAttrs(ctx, level, err.Message(), append(attrs, slog.Group("error", errorAttrs(err)...))...)
+}
+
+func errorAttrs(err *pkgErr.ApplicationError) []any {
+	attrs := []any{
+		slog.String("code", err.Code().String()),
+		slog.String("reason", string(err.Reason())),
+	}
+	if fields := err.Fields(); len(fields) > 0 {
+		invalid := make([]string, 0, len(fields))
+		for _, f := range fields {
+			invalid = append(invalid, f.Field+" "+f.Message)
+		}
+		attrs = append(attrs, slog.Any("fields", invalid))
+	}
+	if cause := err.Unwrap(); cause != nil {
+		attrs = append(attrs, slog.String("cause", cause.Error()))
+	}
+	if err.Level() == pkgErr.LevelError {
+		frames := err.StackTrace()
+		stack := make([]string, 0, len(frames))
+		for _, frame := range frames {
+			stack = append(stack, fmt.Sprintf("%s %s:%d", frame.Function, frame.File, frame.Line))
+		}
+		attrs = append(attrs, slog.Any("stack", stack))
+	}
+	return attrs
+}
+
+// observedResponseWriter keeps the status and the error of a response.
+type observedResponseWriter struct {
+	http.ResponseWriter
+	status int
+	err    *pkgErr.ApplicationError
+}
+
+func (w *observedResponseWriter) WriteHeader(status int) {
+	if w.status == 0 {
+		w.status = status
+	}
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *observedResponseWriter) Write(b []byte) (int, error) {
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+	return w.ResponseWriter.Write(b)
+}
+
+func (w *observedResponseWriter) recordError(err *pkgErr.ApplicationError) {
+	w.err = err
+}
+
+func (w *observedResponseWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+func (w *observedResponseWriter) Status() int {
+	if w.status == 0 {
+		return http.StatusOK
+	}
+	return w.status
+}
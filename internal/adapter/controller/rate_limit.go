@@ -0,0 +1,136 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/Jiei-S/boilerplate-clean-architecture/internal/infrastructure/ratelimit"
+	usecase "github.com/Jiei-S/boilerplate-clean-architecture/internal/usecase"
+	pkgErr "github.com/Jiei-S/boilerplate-clean-architecture/pkg/error"
+)
+
+// RateLimiter takes a token for each request of a client to an operation,
+// and counts the failed authentications of clients.
+type RateLimiter interface {
+	Allow(ctx context.Context, operation, client string) (ratelimit.Decision, error)
+	AllowAuthAttempt(ctx context.Context, client string) (ratelimit.Decision, error)
+	RecordAuthFailure(ctx context.Context, client string) error
+}
+
+// RateLimitMiddleware limits the requests of clients.
+type RateLimitMiddleware struct {
+	limiter    RateLimiter
+	operations Operations
+	logger     *slog.Logger
+}
+
+func NewRateLimitMiddleware(limiter RateLimiter, operations Operations, logger *slog.Logger) *RateLimitMiddleware {
+	return &RateLimitMiddleware{
+		limiter:    limiter,
+		operations: operations,
+		logger:     logger,
+	}
+}
+
+// RateLimit limits the requests of each client to each operation, and
+// reports the limit in the RateLimit-* headers of the response. Clients are
+// identified by their principal, i.e. their API key or user, or else by
+// their address. It must be applied after authentication. Requests are let
+// through if the limiter fails, so that its store is not a single point of
+// failure.
+func (m *RateLimitMiddleware) RateLimit(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		operation := m.operations.Of(r)
+		d, err := m.limiter.Allow(r.Context(), operation, rateLimitClient(r))
+		if err != nil {
+			m.logger.WarnContext(r.Context(), "Rate limiter failed", "error", err)
+			next.ServeHTTP(w, r)
+			return
+		}
+		if d.Limit.Burst == 0 {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		header := w.Header()
+		header.Set("RateLimit-Limit", strconv.Itoa(d.Limit.Burst))
+		header.Set("RateLimit-Remaining", strconv.Itoa(d.Remaining))
+		header.Set("RateLimit-Reset", seconds(d.Reset))
+		header.Set("RateLimit-Policy", fmt.Sprintf("%d;w=%s", d.Limit.Burst, seconds(d.Limit.Period)))
+		if !d.Allowed {
+			header.Set("Retry-After", seconds(d.RetryAfter))
+			HttpError(w, r, pkgErr.NewApplicationError("rate limit exceeded", pkgErr.LevelWarn, pkgErr.CodeTooManyRequests).
+				WithDetail("operation", operation))
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// LimitAuthFailures refuses the requests of an address that failed to
+// authenticate too often, before they cost a lookup of their credentials.
+// It must be applied before authentication, which it counts the 401
+// responses of.
+func (m *RateLimitMiddleware) LimitAuthFailures(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		client := rateLimitClient(r)
+		d, err := m.limiter.AllowAuthAttempt(r.Context(), client)
+		if err != nil {
+			m.logger.WarnContext(r.Context(), "Rate limiter failed", "error", err)
+		} else if !d.Allowed {
+			w.Header().Set("Retry-After", seconds(d.RetryAfter))
+			HttpError(w, r, pkgErr.NewApplicationError("too many failed authentications", pkgErr.LevelWarn, pkgErr.CodeTooManyRequests))
+			return
+		}
+
+		sw := &statusResponseWriter{ResponseWriter: w}
+		next.ServeHTTP(sw, r)
+		if sw.status == http.StatusUnauthorized {
+			if err := m.limiter.RecordAuthFailure(r.Context(), client); err != nil {
+				m.logger.WarnContext(r.Context(), "Rate limiter failed", "error", err)
+			}
+		}
+	})
+}
+
+// statusResponseWriter keeps the status of a response.
+type statusResponseWriter struct {
+	http.ResponseWriter
+	status int
+}
+
+func (w *statusResponseWriter) WriteHeader(status int) {
+	if w.status == 0 {
+		w.status = status
+	}
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *statusResponseWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// rateLimitClient identifies the client of r by its principal, or else by
+// its address, which is that of the last proxy unless the server trusts
+// proxy headers.
+func rateLimitClient(r *http.Request) string {
+	if p, ok := usecase.PrincipalFrom(r.Context()); ok {
+		return "principal:" + p.Subject
+	}
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+	return "ip:" + host
+}
+
+// seconds rounds d up to whole seconds, as RateLimit-* headers count.
+func seconds(d time.Duration) string {
+	return strconv.Itoa(int(math.Ceil(d.Seconds())))
+}
@@ -0,0 +1,53 @@
+package controller
+
+import (
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/Jiei-S/boilerplate-clean-architecture/internal/infrastructure/config"
+	"github.com/Jiei-S/boilerplate-clean-architecture/internal/infrastructure/ratelimit"
+	pkgErr "github.com/Jiei-S/boilerplate-clean-architecture/pkg/error"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLimitAuthFailures(t *testing.T) {
+	m := NewRateLimitMiddleware(ratelimit.New(config.RateLimitConfig{
+		Enabled:      true,
+		AuthFailures: config.LimitConfig{Limit: 3, Period: time.Minute},
+	}, ratelimit.NewMemoryStore()), Operations{}, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	var calls int
+	handler := m.LimitAuthFailures(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if r.Header.Get("X-API-Key") != "valid" {
+			HttpError(w, r, pkgErr.NewApplicationError("invalid API key", pkgErr.LevelInfo, pkgErr.CodeUnauthorized))
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	send := func(remoteAddr, key string) *httptest.ResponseRecorder {
+		r := httptest.NewRequest(http.MethodGet, "/users", nil)
+		r.RemoteAddr = remoteAddr
+		r.Header.Set("X-API-Key", key)
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, r)
+		return w
+	}
+
+	for i := 0; i < 3; i++ {
+		assert.Equal(t, http.StatusUnauthorized, send("192.0.2.1:1234", "guess").Code)
+	}
+	w := send("192.0.2.1:1234", "guess")
+	assert.Equal(t, http.StatusTooManyRequests, w.Code)
+	assert.Equal(t, "20", w.Header().Get("Retry-After"))
+	// The address is refused before its credentials are checked, even valid
+	// ones.
+	assert.Equal(t, http.StatusTooManyRequests, send("192.0.2.1:1234", "valid").Code)
+	assert.Equal(t, 3, calls)
+
+	// Other addresses are not affected.
+	assert.Equal(t, http.StatusOK, send("192.0.2.2:1234", "valid").Code)
+}
@@ -0,0 +1,25 @@
+package controller
+
+import (
+	"fmt"
+	"net/http"
+
+	pkgErr "github.com/Jiei-S/boilerplate-clean-architecture/pkg/error"
+)
+
+// Recovery reports a panic of the handler as a 500 instead of dropping the
+// connection.
+func Recovery(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		defer func() {
+			if rec := recover(); rec != nil && rec != http.ErrAbortHandler {
+				err, ok := rec.(error)
+				if !ok {
+					err = fmt.Errorf("%v", rec)
+				}
+				HttpError(w, r, pkgErr.Wrap(err, "panic recovered", pkgErr.LevelError, pkgErr.CodeInternalServerError))
+			}
+		}()
+		next.ServeHTTP(w, r)
+	})
+}
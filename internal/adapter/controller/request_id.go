@@ -0,0 +1,42 @@
+package controller
+
+import (
+	"context"
+	"net/http"
+	"regexp"
+
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/google/uuid"
+)
+
+// RequestIDHeader carries the ID of a request in both directions.
+const RequestIDHeader = "X-Request-ID"
+
+// requestIDPattern keeps IDs from clients short and free of characters that
+// could forge log lines.
+var requestIDPattern = regexp.MustCompile(`^[\w.:/+=@-]{1,128}$`)
+
+// RequestID gives each request an ID: the X-Request-ID header of the
+// request if it has a valid one, or a new UUID. The ID is put in the
+// context, where request logs pick it up, and in the response header,
+// where error bodies pick it up. It must wrap every other middleware.
+func RequestID(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := r.Header.Get(RequestIDHeader)
+		if !requestIDPattern.MatchString(id) {
+			id = uuid.NewString()
+			// Inner middlewares that read the header, such as the one of
+			// httplog, must see the same ID.
+			r.Header.Set(RequestIDHeader, id)
+		}
+
+		w.Header().Set(RequestIDHeader, id)
+		ctx := context.WithValue(r.Context(), middleware.RequestIDKey, id)
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// RequestIDFromContext returns the ID of the request that ctx belongs to.
+func RequestIDFromContext(ctx context.Context) string {
+	return middleware.GetReqID(ctx)
+}
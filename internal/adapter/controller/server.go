@@ -0,0 +1,25 @@
+package controller
+
+import (
+	rest "github.com/Jiei-S/boilerplate-clean-architecture/internal/infrastructure/openapi"
+)
+
+var _ rest.ServerInterface = (*Server)(nil)
+
+// Server composes the handlers of each group of endpoints into the server
+// interface generated from api/openapi.yaml.
+type Server struct {
+	*UserHandler
+	*APIKeyHandler
+	*HealthHandler
+	*MetricsHandler
+}
+
+func NewServer(users *UserHandler, apiKeys *APIKeyHandler, health *HealthHandler, metrics *MetricsHandler) *Server {
+	return &Server{
+		UserHandler:    users,
+		APIKeyHandler:  apiKeys,
+		HealthHandler:  health,
+		MetricsHandler: metrics,
+	}
+}
@@ -0,0 +1,219 @@
+package controller
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+
+	usecase "github.com/Jiei-S/boilerplate-clean-architecture/internal/usecase"
+	pkgErr "github.com/Jiei-S/boilerplate-clean-architecture/pkg/error"
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/go-chi/chi/v5"
+)
+
+const (
+	txExtension          = "x-transaction"
+	txIsolationExtension = "x-transaction-isolation"
+)
+
+var isolationLevels = map[string]sql.IsolationLevel{
+	"read-uncommitted": sql.LevelReadUncommitted,
+	"read-committed":   sql.LevelReadCommitted,
+	"repeatable-read":  sql.LevelRepeatableRead,
+	"serializable":     sql.LevelSerializable,
+}
+
+// errRollback makes SetDBMiddleware roll back the transaction of a request
+// whose handler did not succeed.
+var errRollback = pkgErr.NewApplicationError("transaction rolled back", pkgErr.LevelInfo, pkgErr.CodeInternalServerError)
+
+// TxPolicy holds the transaction options of each route, keyed by
+// "METHOD /path/{pattern}". Reads run in a read-only transaction, and an
+// operation can pick its isolation level with the x-transaction-isolation
+// extension in api/openapi.yaml. An operation that must not touch the
+// database, e.g. a health check, opts out with x-transaction: false and is
+// kept with nil options.
+type TxPolicy map[string]*sql.TxOptions
+
+// Options returns the transaction options of the route of r, and false if it
+// runs without a transaction.
+func (p TxPolicy) Options(r *http.Request) (*sql.TxOptions, bool) {
+	if opts, ok := p[r.Method+" "+chi.RouteContext(r.Context()).RoutePattern()]; ok {
+		return opts, opts != nil
+	}
+	return &sql.TxOptions{
+		ReadOnly: isReadMethod(r.Method),
+	}, true
+}
+
+func NewTxPolicy(swagger *openapi3.T) (TxPolicy, error) {
+	policy := TxPolicy{}
+	for path, item := range swagger.Paths {
+		for method, op := range item.Operations() {
+			if v, ok := op.Extensions[txExtension]; ok {
+				if v != false {
+					return nil, fmt.Errorf("%s %s: %s must be false if set, got %v", method, path, txExtension, v)
+				}
+				policy[method+" "+path] = nil
+				continue
+			}
+
+			opts := &sql.TxOptions{
+				ReadOnly: isReadMethod(method),
+			}
+			if v, ok := op.Extensions[txIsolationExtension]; ok {
+				level, ok := isolationLevels[fmt.Sprint(v)]
+				if !ok {
+					return nil, fmt.Errorf("%s %s: unknown %s %q", method, path, txIsolationExtension, v)
+				}
+				opts.Isolation = level
+			}
+			policy[method+" "+path] = opts
+		}
+	}
+	return policy, nil
+}
+
+func isReadMethod(method string) bool {
+	return method == http.MethodGet || method == http.MethodHead
+}
+
+// TxMiddleware runs requests in transactions.
+type TxMiddleware struct {
+	transactor usecase.Transactor
+	txPolicy   TxPolicy
+}
+
+// SetDBMiddleware runs each request in a transaction that is committed only
+// if the handler succeeded, unless its operation opted out with
+// x-transaction: false. The handler is run again if the transaction is
+// retried, so the response is held back until the transaction is settled,
+// and the body is buffered to be read again; LimitRequestBody bounds it.
+// It must be applied after routing, because the transaction options depend
+// on the matched route.
+func (m *TxMiddleware) SetDBMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		opts, ok := m.txPolicy.Options(r)
+		if !ok {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		body, readErr := io.ReadAll(r.Body)
+		var tooLarge *http.MaxBytesError
+		switch {
+		case errors.As(readErr, &tooLarge):
+			HttpError(w, r, pkgErr.Wrap(readErr, "request body too large", pkgErr.LevelWarn, pkgErr.CodeRequestTooLarge).
+				WithDetail("maxBytes", tooLarge.Limit))
+			return
+		case readErr != nil:
+			HttpError(w, r, pkgErr.Wrap(readErr, "failed to read request body", pkgErr.LevelWarn, pkgErr.CodeBadRequest))
+			return
+		}
+
+		ww := newTxResponseWriter(w)
+		err := m.transactor.RunInTx(r.Context(), opts, func(ctx context.Context) *pkgErr.ApplicationError {
+			ww.reset()
+			r.Body = io.NopCloser(bytes.NewReader(body))
+			next.ServeHTTP(ww, r.WithContext(ctx))
+
+			switch {
+			case ctx.Err() != nil:
+				return errRollback
+			case ww.Status() < http.StatusBadRequest:
+				return nil
+			case ww.err != nil:
+				// Let the transactor decide whether the failure is worth a retry.
+				return ww.err
+			default:
+				return errRollback
+			}
+		})
+
+		switch {
+		case r.Context().Err() != nil:
+			// The client has gone away, so there is nobody to respond to.
+			return
+		case err != nil && ww.Status() < http.StatusBadRequest:
+			// The handler succeeded but the commit failed.
+			HttpError(w, r, err)
+			return
+		}
+		ww.flush(w)
+	})
+}
+
+func NewTxMiddleware(transactor usecase.Transactor, txPolicy TxPolicy) *TxMiddleware {
+	return &TxMiddleware{
+		transactor: transactor,
+		txPolicy:   txPolicy,
+	}
+}
+
+// txResponseWriter holds the response back until the transaction of the
+// request is settled, so that a failed commit is not reported as a success.
+// It starts from the headers that outer middlewares already set, such as
+// X-Request-ID.
+type txResponseWriter struct {
+	base   http.Header
+	header http.Header
+	status int
+	body   bytes.Buffer
+	err    *pkgErr.ApplicationError
+}
+
+func newTxResponseWriter(dst http.ResponseWriter) *txResponseWriter {
+	return &txResponseWriter{
+		base:   dst.Header().Clone(),
+		header: dst.Header().Clone(),
+	}
+}
+
+func (w *txResponseWriter) Header() http.Header {
+	return w.header
+}
+
+func (w *txResponseWriter) WriteHeader(status int) {
+	if w.status == 0 {
+		w.status = status
+	}
+}
+
+func (w *txResponseWriter) Write(b []byte) (int, error) {
+	w.WriteHeader(http.StatusOK)
+	return w.body.Write(b)
+}
+
+// recordError keeps the error that the handler responded with.
+func (w *txResponseWriter) recordError(err *pkgErr.ApplicationError) {
+	w.err = err
+}
+
+func (w *txResponseWriter) reset() {
+	w.header = w.base.Clone()
+	w.status = 0
+	w.body.Reset()
+	w.err = nil
+}
+
+func (w *txResponseWriter) Status() int {
+	if w.status == 0 {
+		return http.StatusOK
+	}
+	return w.status
+}
+
+func (w *txResponseWriter) flush(dst http.ResponseWriter) {
+	if w.err != nil {
+		recordError(dst, w.err)
+	}
+	for k, v := range w.header {
+		dst.Header()[k] = v
+	}
+	dst.WriteHeader(w.Status())
+	dst.Write(w.body.Bytes())
+}
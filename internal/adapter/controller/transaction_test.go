@@ -0,0 +1,185 @@
+package controller
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/Jiei-S/boilerplate-clean-architecture/internal/adapter/gateway"
+	"github.com/Jiei-S/boilerplate-clean-architecture/internal/domain/entity"
+	"github.com/Jiei-S/boilerplate-clean-architecture/internal/usecase"
+	pkgErr "github.com/Jiei-S/boilerplate-clean-architecture/pkg/error"
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// recordingTransactor passes the transactions to the memory transactor,
+// keeping their options, and retries them as the database transactor does
+// when they fail with a transient error.
+type recordingTransactor struct {
+	usecase.Transactor
+	opts     []*sql.TxOptions
+	attempts int
+}
+
+func (t *recordingTransactor) RunInTx(
+	ctx context.Context,
+	opts *sql.TxOptions,
+	fn func(ctx context.Context) *pkgErr.ApplicationError,
+) *pkgErr.ApplicationError {
+	t.opts = append(t.opts, opts)
+	var err *pkgErr.ApplicationError
+	for attempt := 0; attempt < 2; attempt++ {
+		t.attempts++
+		err = t.Transactor.RunInTx(ctx, opts, fn)
+		if err == nil || err.Code() != pkgErr.CodeUnavailable {
+			return err
+		}
+	}
+	return err
+}
+
+func TestSetDBMiddleware(t *testing.T) {
+	// newServer routes /users and the opted-out POST /users/bulk through
+	// SetDBMiddleware to handle, which gets a repository on the store of the
+	// transactor.
+	newServer := func(handle func(w http.ResponseWriter, r *http.Request, repo usecase.UserRepository)) (http.Handler, *recordingTransactor, usecase.UserRepository) {
+		store := gateway.NewMemoryStore()
+		repo := gateway.NewMemoryUserRepository(store)
+		tx := &recordingTransactor{Transactor: gateway.NewMemoryTransactor(store)}
+		m := NewTxMiddleware(tx, TxPolicy{
+			"POST /users":      {},
+			"GET /users":       {ReadOnly: true},
+			"POST /users/bulk": nil,
+		})
+
+		r := chi.NewRouter()
+		r.Use(Recovery)
+		r.With(m.SetDBMiddleware).HandleFunc("/users", func(w http.ResponseWriter, r *http.Request) {
+			handle(w, r, repo)
+		})
+		r.With(m.SetDBMiddleware).HandleFunc("/users/bulk", func(w http.ResponseWriter, r *http.Request) {
+			handle(w, r, repo)
+		})
+		return r, tx, repo
+	}
+	// saveUser saves a user named after the body of the request.
+	saveUser := func(t *testing.T, r *http.Request, repo usecase.UserRepository) {
+		name, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		user, appErr := entity.NewUser(string(name), "user", 20)
+		require.Nil(t, appErr)
+		_, appErr = repo.Save(r.Context(), user)
+		require.Nil(t, appErr)
+	}
+	countUsers := func(t *testing.T, repo usecase.UserRepository) int {
+		users, err := repo.List(context.Background(), &usecase.UserCriteria{Limit: 10})
+		require.Nil(t, err)
+		return len(users)
+	}
+	send := func(h http.Handler, r *http.Request) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, r)
+		return w
+	}
+
+	t.Run("commits on success", func(t *testing.T) {
+		h, tx, repo := newServer(func(w http.ResponseWriter, r *http.Request, repo usecase.UserRepository) {
+			saveUser(t, r, repo)
+			w.WriteHeader(http.StatusCreated)
+		})
+		w := send(h, httptest.NewRequest(http.MethodPost, "/users", strings.NewReader("test")))
+		assert.Equal(t, http.StatusCreated, w.Code)
+		assert.Equal(t, 1, countUsers(t, repo))
+		require.Len(t, tx.opts, 1)
+		assert.False(t, tx.opts[0].ReadOnly)
+	})
+
+	t.Run("rolls back on an error status", func(t *testing.T) {
+		h, _, repo := newServer(func(w http.ResponseWriter, r *http.Request, repo usecase.UserRepository) {
+			saveUser(t, r, repo)
+			HttpError(w, r, pkgErr.NewApplicationError("conflict", pkgErr.LevelWarn, pkgErr.CodeConflict))
+		})
+		w := send(h, httptest.NewRequest(http.MethodPost, "/users", strings.NewReader("test")))
+		assert.Equal(t, http.StatusConflict, w.Code)
+		assert.Zero(t, countUsers(t, repo))
+	})
+
+	t.Run("rolls back on panic", func(t *testing.T) {
+		h, _, repo := newServer(func(w http.ResponseWriter, r *http.Request, repo usecase.UserRepository) {
+			saveUser(t, r, repo)
+			panic("boom")
+		})
+		w := send(h, httptest.NewRequest(http.MethodPost, "/users", strings.NewReader("test")))
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+		assert.Zero(t, countUsers(t, repo))
+	})
+
+	t.Run("rolls back and does not respond when the client has gone", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		h, _, repo := newServer(func(w http.ResponseWriter, r *http.Request, repo usecase.UserRepository) {
+			saveUser(t, r, repo)
+			cancel()
+			w.WriteHeader(http.StatusCreated)
+		})
+		w := send(h, httptest.NewRequest(http.MethodPost, "/users", strings.NewReader("test")).WithContext(ctx))
+		assert.False(t, w.Flushed)
+		assert.Empty(t, w.Body.String())
+		assert.Zero(t, countUsers(t, repo))
+	})
+
+	t.Run("reruns the handler with the same body on retry", func(t *testing.T) {
+		var bodies []string
+		h, tx, repo := newServer(func(w http.ResponseWriter, r *http.Request, repo usecase.UserRepository) {
+			name, err := io.ReadAll(r.Body)
+			require.NoError(t, err)
+			bodies = append(bodies, string(name))
+			user, appErr := entity.NewUser(string(name), fmt.Sprint("attempt", len(bodies)), 20)
+			require.Nil(t, appErr)
+			_, appErr = repo.Save(r.Context(), user)
+			require.Nil(t, appErr)
+			if len(bodies) == 1 {
+				w.Header().Set("X-Attempt", "first")
+				HttpError(w, r, pkgErr.NewApplicationError("deadlock", pkgErr.LevelWarn, pkgErr.CodeUnavailable))
+				return
+			}
+			w.WriteHeader(http.StatusCreated)
+		})
+		w := send(h, httptest.NewRequest(http.MethodPost, "/users", strings.NewReader("test")))
+		assert.Equal(t, http.StatusCreated, w.Code)
+		assert.Equal(t, 2, tx.attempts)
+		assert.Equal(t, []string{"test", "test"}, bodies)
+		// Only the response and the writes of the last attempt are kept.
+		assert.Empty(t, w.Header().Get("X-Attempt"))
+		assert.Equal(t, 1, countUsers(t, repo))
+	})
+
+	t.Run("uses a read-only transaction for reads", func(t *testing.T) {
+		h, tx, _ := newServer(func(w http.ResponseWriter, r *http.Request, repo usecase.UserRepository) {
+			w.WriteHeader(http.StatusOK)
+		})
+		w := send(h, httptest.NewRequest(http.MethodGet, "/users", nil))
+		assert.Equal(t, http.StatusOK, w.Code)
+		require.Len(t, tx.opts, 1)
+		assert.True(t, tx.opts[0].ReadOnly)
+	})
+
+	t.Run("runs opted-out operations without a transaction", func(t *testing.T) {
+		h, tx, repo := newServer(func(w http.ResponseWriter, r *http.Request, repo usecase.UserRepository) {
+			saveUser(t, r, repo)
+			HttpError(w, r, pkgErr.NewApplicationError("conflict", pkgErr.LevelWarn, pkgErr.CodeConflict))
+		})
+		w := send(h, httptest.NewRequest(http.MethodPost, "/users/bulk", strings.NewReader("test")))
+		assert.Equal(t, http.StatusConflict, w.Code)
+		assert.Empty(t, tx.opts)
+		// Nothing rolls back the write.
+		assert.Equal(t, 1, countUsers(t, repo))
+	})
+}
@@ -1,36 +1,28 @@
 package controller
 
 import (
-	"context"
 	"encoding/json"
 	"net/http"
 
-	"github.com/Jiei-S/boilerplate-clean-architecture/internal/adapter/gateway"
 	rest "github.com/Jiei-S/boilerplate-clean-architecture/internal/infrastructure/openapi"
 	usecase "github.com/Jiei-S/boilerplate-clean-architecture/internal/usecase"
-	"github.com/go-chi/chi/v5/middleware"
-	"github.com/uptrace/bun"
-
-	pkgErr "github.com/Jiei-S/boilerplate-clean-architecture/pkg/error"
 )
 
-var _ rest.ServerInterface = (*UserHandler)(nil)
-
+// UserHandler serves the user endpoints.
 type UserHandler struct {
-	db      *bun.DB
 	usecase usecase.UserUsecase
 }
 
-func (h *UserHandler) AddUser(w http.ResponseWriter, r *http.Request) {
+func (h *UserHandler) AddUser(w http.ResponseWriter, r *http.Request, params rest.AddUserParams) {
 	req, err := ToDTO(r.Body)
 	if err != nil {
-		HttpError(w, err)
+		HttpError(w, r, err)
 		return
 	}
 
-	result, err := h.usecase.AddUser(r.Context(), req)
+	result, err := h.usecase.AddUser(r.Context(), req, ToIdempotencyKey(params))
 	if err != nil {
-		HttpError(w, err)
+		HttpError(w, r, err)
 		return
 	}
 
@@ -40,56 +32,88 @@ func (h *UserHandler) AddUser(w http.ResponseWriter, r *http.Request) {
 func (h *UserHandler) FindUser(w http.ResponseWriter, r *http.Request, id string) {
 	result, err := h.usecase.FindUser(r.Context(), id)
 	if err != nil {
-		HttpError(w, err)
+		HttpError(w, r, err)
+		return
+	}
+
+	h.HandleOK(w, FromDTO(result))
+}
+
+func (h *UserHandler) ListUsers(w http.ResponseWriter, r *http.Request, params rest.ListUsersParams) {
+	result, err := h.usecase.ListUsers(r.Context(), ToListDTO(params))
+	if err != nil {
+		HttpError(w, r, err)
+		return
+	}
+
+	h.HandleOK(w, FromListDTO(result))
+}
+
+func (h *UserHandler) UpdateUser(w http.ResponseWriter, r *http.Request, id string) {
+	req, err := ToDTO(r.Body)
+	if err != nil {
+		HttpError(w, r, err)
+		return
+	}
+
+	result, err := h.usecase.UpdateUser(r.Context(), id, req)
+	if err != nil {
+		HttpError(w, r, err)
+		return
+	}
+
+	h.HandleOK(w, FromDTO(result))
+}
+
+func (h *UserHandler) PatchUser(w http.ResponseWriter, r *http.Request, id string) {
+	req, err := ToPatchDTO(r.Body)
+	if err != nil {
+		HttpError(w, r, err)
+		return
+	}
+
+	result, err := h.usecase.PatchUser(r.Context(), id, req)
+	if err != nil {
+		HttpError(w, r, err)
 		return
 	}
 
 	h.HandleOK(w, FromDTO(result))
 }
 
-func (h *UserHandler) Health(w http.ResponseWriter, r *http.Request) {
-	h.HandleOK(w, rest.Health{
-		Status: rest.Healthy,
-	})
+func (h *UserHandler) DeleteUser(w http.ResponseWriter, r *http.Request, id string) {
+	if err := h.usecase.DeleteUser(r.Context(), id); err != nil {
+		HttpError(w, r, err)
+		return
+	}
+
+	h.HandleNoContent(w)
 }
 
 func (h *UserHandler) HandleOK(w http.ResponseWriter, obj interface{}) {
+	handleOK(w, obj)
+}
+
+func (h *UserHandler) HandleNoContent(w http.ResponseWriter) {
+	handleNoContent(w)
+}
+
+func handleOK(w http.ResponseWriter, obj interface{}) {
 	setHeaderContentType(w)
 	w.WriteHeader(http.StatusOK)
 	json.NewEncoder(w).Encode(obj)
 }
 
-func setHeaderContentType(w http.ResponseWriter) {
-	w.Header().Set("Content-Type", "application/json; charset=UTF-8")
-}
-
-func (h *UserHandler) Recovery(next http.Handler) http.Handler {
-	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		defer func() {
-			if err := recover(); err != nil && err != http.ErrAbortHandler {
-				HttpError(w, pkgErr.NewApplicationError(err.(error).Error(), pkgErr.LevelError, pkgErr.CodeInternalServerError))
-			}
-		}()
-		next.ServeHTTP(w, r)
-	})
+func handleNoContent(w http.ResponseWriter) {
+	w.WriteHeader(http.StatusNoContent)
 }
 
-func (h *UserHandler) SetDBMiddleware(next http.Handler) http.Handler {
-	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		h.db.RunInTx(r.Context(), nil, func(ctx context.Context, tx bun.Tx) error {
-			ww := middleware.NewWrapResponseWriter(w, r.ProtoMajor)
-			next.ServeHTTP(ww, r.WithContext(context.WithValue(r.Context(), gateway.TX_KEY, &tx)))
-			return nil
-		})
-	})
+func setHeaderContentType(w http.ResponseWriter) {
+	w.Header().Set("Content-Type", "application/json; charset=UTF-8")
 }
 
-func NewUserHandler(
-	db *bun.DB,
-	usecase usecase.UserUsecase,
-) *UserHandler {
+func NewUserHandler(usecase usecase.UserUsecase) *UserHandler {
 	return &UserHandler{
-		db:      db,
 		usecase: usecase,
 	}
 }
@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"io"
 	"net/http"
+	"strings"
 
 	rest "github.com/Jiei-S/boilerplate-clean-architecture/internal/infrastructure/openapi"
 	usecase "github.com/Jiei-S/boilerplate-clean-architecture/internal/usecase"
@@ -15,7 +16,7 @@ func ToDTO(
 ) (*usecase.User, *pkgErr.ApplicationError) {
 	var dto usecase.User
 	if err := json.NewDecoder(body).Decode(&dto); err != nil {
-		return nil, pkgErr.NewApplicationError("failed to decode request body", pkgErr.LevelWarn, pkgErr.CodeBadRequest)
+		return nil, pkgErr.Wrap(err, "failed to decode request body", pkgErr.LevelWarn, pkgErr.CodeBadRequest)
 	}
 	return &usecase.User{
 		ID:        dto.ID,
@@ -25,6 +26,29 @@ func ToDTO(
 	}, nil
 }
 
+func ToPatchDTO(
+	body io.ReadCloser,
+) (*usecase.UserPatch, *pkgErr.ApplicationError) {
+	var dto rest.PatchUserJSONRequestBody
+	if err := json.NewDecoder(body).Decode(&dto); err != nil {
+		return nil, pkgErr.Wrap(err, "failed to decode request body", pkgErr.LevelWarn, pkgErr.CodeBadRequest)
+	}
+	return &usecase.UserPatch{
+		FirstName: dto.FirstName,
+		LastName:  dto.LastName,
+		Age:       dto.Age,
+	}, nil
+}
+
+// ToIdempotencyKey returns the Idempotency-Key header of the request, or ""
+// if it has none.
+func ToIdempotencyKey(params rest.AddUserParams) string {
+	if params.IdempotencyKey == nil {
+		return ""
+	}
+	return *params.IdempotencyKey
+}
+
 func FromDTO(
 	dto *usecase.User,
 ) *rest.User {
@@ -36,51 +60,233 @@ func FromDTO(
 	}
 }
 
-func NotFoundError(w http.ResponseWriter, err *pkgErr.ApplicationError) {
-	setHeaderContentType(w)
-	w.WriteHeader(http.StatusNotFound)
-	json.NewEncoder(w).Encode(rest.Error{
-		Code:    http.StatusNotFound,
-		Message: err.Error(),
-	})
+func ToListDTO(
+	params rest.ListUsersParams,
+) *usecase.UserListQuery {
+	dto := &usecase.UserListQuery{
+		FirstName: params.FirstName,
+		LastName:  params.LastName,
+		MinAge:    params.MinAge,
+		MaxAge:    params.MaxAge,
+	}
+	if params.Sort != nil {
+		dto.Sort = string(*params.Sort)
+	}
+	if params.Limit != nil {
+		dto.Limit = *params.Limit
+	}
+	if params.Cursor != nil {
+		dto.Cursor = *params.Cursor
+	}
+	return dto
 }
 
-func BadRequestError(w http.ResponseWriter, err *pkgErr.ApplicationError) {
-	setHeaderContentType(w)
-	w.WriteHeader(http.StatusBadRequest)
-	json.NewEncoder(w).Encode(rest.Error{
-		Code:    http.StatusBadRequest,
-		Message: err.Error(),
-	})
+func FromListDTO(
+	dto *usecase.UserList,
+) *rest.UserList {
+	list := &rest.UserList{
+		Items: make([]rest.User, 0, len(dto.Users)),
+	}
+	for _, user := range dto.Users {
+		list.Items = append(list.Items, *FromDTO(user))
+	}
+	if dto.NextCursor != "" {
+		list.NextCursor = &dto.NextCursor
+	}
+	return list
+}
+
+// FromApplicationError renders err as RFC 9457 problem details. The detail
+// of a server error is replaced with a generic message, so that database
+// and driver messages never reach clients.
+func FromApplicationError(
+	instance string,
+	requestID string,
+	status int,
+	err *pkgErr.ApplicationError,
+) *rest.Problem {
+	problem := &rest.Problem{
+		Type:   problemType(err.Reason()),
+		Title:  http.StatusText(status),
+		Status: int32(status),
+		Reason: string(err.Reason()),
+	}
+	if instance != "" {
+		problem.Instance = &instance
+	}
+	if requestID != "" {
+		problem.RequestId = &requestID
+	}
+	if status >= http.StatusInternalServerError {
+		detail := "an unexpected error occurred"
+		problem.Type = "about:blank"
+		problem.Detail = &detail
+		return problem
+	}
+
+	detail := err.Message()
+	problem.Detail = &detail
+	problem.Fields = FromFieldErrors(err.Fields())
+	if details := err.Details(); len(details) > 0 {
+		problem.Details = &details
+	}
+	return problem
+}
+
+// problemType identifies the problem type by its reason, e.g.
+// urn:problem-type:user-not-found for USER_NOT_FOUND.
+func problemType(reason pkgErr.Reason) string {
+	return "urn:problem-type:" + strings.ToLower(strings.ReplaceAll(string(reason), "_", "-"))
 }
 
-func DuplicateError(w http.ResponseWriter, err *pkgErr.ApplicationError) {
-	setHeaderContentType(w)
-	w.WriteHeader(http.StatusConflict)
-	json.NewEncoder(w).Encode(rest.Error{
-		Code:    http.StatusConflict,
-		Message: err.Error(),
-	})
+func FromFieldErrors(fields []pkgErr.FieldError) *[]rest.FieldError {
+	if len(fields) == 0 {
+		return nil
+	}
+
+	result := make([]rest.FieldError, 0, len(fields))
+	for _, f := range fields {
+		result = append(result, rest.FieldError{
+			Field:   f.Field,
+			Message: f.Message,
+		})
+	}
+	return &result
 }
 
-func InternalServerError(w http.ResponseWriter, err *pkgErr.ApplicationError) {
-	setHeaderContentType(w)
-	w.WriteHeader(http.StatusInternalServerError)
-	json.NewEncoder(w).Encode(rest.Error{
-		Code:    http.StatusInternalServerError,
-		Message: err.Error(),
-	})
+func NotFoundError(w http.ResponseWriter, r *http.Request, err *pkgErr.ApplicationError) {
+	writeProblem(w, r.URL.Path, http.StatusNotFound, err)
 }
 
-func HttpError(w http.ResponseWriter, err *pkgErr.ApplicationError) {
+func BadRequestError(w http.ResponseWriter, r *http.Request, err *pkgErr.ApplicationError) {
+	writeProblem(w, r.URL.Path, http.StatusBadRequest, err)
+}
+
+func DuplicateError(w http.ResponseWriter, r *http.Request, err *pkgErr.ApplicationError) {
+	writeProblem(w, r.URL.Path, http.StatusConflict, err)
+}
+
+func ConflictError(w http.ResponseWriter, r *http.Request, err *pkgErr.ApplicationError) {
+	writeProblem(w, r.URL.Path, http.StatusConflict, err)
+}
+
+// UnauthorizedError asks the client to authenticate with a bearer token.
+func UnauthorizedError(w http.ResponseWriter, r *http.Request, err *pkgErr.ApplicationError) {
+	w.Header().Set("WWW-Authenticate", bearerChallenge)
+	writeProblem(w, r.URL.Path, http.StatusUnauthorized, err)
+}
+
+// ForbiddenError reports a request that the caller is not permitted to make.
+func ForbiddenError(w http.ResponseWriter, r *http.Request, err *pkgErr.ApplicationError) {
+	writeProblem(w, r.URL.Path, http.StatusForbidden, err)
+}
+
+// TooManyRequestsError reports a request over the rate limit of the caller,
+// whose headers tell when to retry.
+func TooManyRequestsError(w http.ResponseWriter, r *http.Request, err *pkgErr.ApplicationError) {
+	writeProblem(w, r.URL.Path, http.StatusTooManyRequests, err)
+}
+
+// UnprocessableError reports a well-formed request that cannot be carried
+// out.
+func UnprocessableError(w http.ResponseWriter, r *http.Request, err *pkgErr.ApplicationError) {
+	writeProblem(w, r.URL.Path, http.StatusUnprocessableEntity, err)
+}
+
+// RequestTooLargeError reports a request body over the limit of the server.
+func RequestTooLargeError(w http.ResponseWriter, r *http.Request, err *pkgErr.ApplicationError) {
+	writeProblem(w, r.URL.Path, http.StatusRequestEntityTooLarge, err)
+}
+
+// ServiceUnavailableError reports a transient failure, which clients may
+// retry after a moment.
+func ServiceUnavailableError(w http.ResponseWriter, r *http.Request, err *pkgErr.ApplicationError) {
+	w.Header().Set("Retry-After", "1")
+	writeProblem(w, r.URL.Path, http.StatusServiceUnavailable, err)
+}
+
+func InternalServerError(w http.ResponseWriter, r *http.Request, err *pkgErr.ApplicationError) {
+	writeProblem(w, r.URL.Path, http.StatusInternalServerError, err)
+}
+
+func HttpError(w http.ResponseWriter, r *http.Request, err *pkgErr.ApplicationError) {
 	switch err.Code() {
 	case pkgErr.CodeBadRequest:
-		BadRequestError(w, err)
+		BadRequestError(w, r, err)
 	case pkgErr.CodeNotFound:
-		NotFoundError(w, err)
+		NotFoundError(w, r, err)
 	case pkgErr.CodeDuplicate:
-		DuplicateError(w, err)
+		DuplicateError(w, r, err)
+	case pkgErr.CodeConflict:
+		ConflictError(w, r, err)
+	case pkgErr.CodeUnavailable:
+		ServiceUnavailableError(w, r, err)
+	case pkgErr.CodeUnauthorized:
+		UnauthorizedError(w, r, err)
+	case pkgErr.CodeForbidden:
+		ForbiddenError(w, r, err)
+	case pkgErr.CodeTooManyRequests:
+		TooManyRequestsError(w, r, err)
+	case pkgErr.CodeUnprocessable:
+		UnprocessableError(w, r, err)
+	case pkgErr.CodeRequestTooLarge:
+		RequestTooLargeError(w, r, err)
+	default:
+		InternalServerError(w, r, err)
+	}
+}
+
+// ParamError reports request parameters that the generated router could not
+// bind.
+func ParamError(w http.ResponseWriter, r *http.Request, err error) {
+	HttpError(w, r, pkgErr.Wrap(err, "invalid request parameter", pkgErr.LevelWarn, pkgErr.CodeBadRequest))
+}
+
+// RequestValidationError reports requests rejected by the OpenAPI request
+// validator. The validator does not pass the request along, so the problem
+// has no instance.
+func RequestValidationError(w http.ResponseWriter, message string, status int) {
+	switch status {
+	case http.StatusNotFound:
+		writeProblem(w, "", status, pkgErr.NewApplicationError(message, pkgErr.LevelInfo, pkgErr.CodeNotFound))
+	case http.StatusBadRequest:
+		writeProblem(w, "", status, pkgErr.NewApplicationError(message, pkgErr.LevelWarn, pkgErr.CodeBadRequest))
+	case http.StatusUnauthorized:
+		w.Header().Set("WWW-Authenticate", bearerChallenge)
+		writeProblem(w, "", status, pkgErr.NewApplicationError(message, pkgErr.LevelInfo, pkgErr.CodeUnauthorized))
 	default:
-		InternalServerError(w, err)
+		writeProblem(w, "", status, pkgErr.NewApplicationError(message, pkgErr.LevelError, pkgErr.CodeInternalServerError))
+	}
+}
+
+// errorRecorder is a response writer that keeps the error a request failed
+// with, for the middlewares that wrap the handler.
+type errorRecorder interface {
+	recordError(err *pkgErr.ApplicationError)
+}
+
+// recordError hands err to the innermost errorRecorder that w wraps,
+// looking through the writers of other middlewares.
+func recordError(w http.ResponseWriter, err *pkgErr.ApplicationError) {
+	for {
+		switch ww := w.(type) {
+		case errorRecorder:
+			ww.recordError(err)
+			return
+		case interface{ Unwrap() http.ResponseWriter }:
+			w = ww.Unwrap()
+		default:
+			return
+		}
 	}
 }
+
+// writeProblem takes the request ID from the response header set by
+// RequestID, because the request validator reports errors without the
+// request.
+func writeProblem(w http.ResponseWriter, instance string, status int, err *pkgErr.ApplicationError) {
+	recordError(w, err)
+	w.Header().Set("Content-Type", "application/problem+json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(FromApplicationError(instance, w.Header().Get(RequestIDHeader), status, err))
+}
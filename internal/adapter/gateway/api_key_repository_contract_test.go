@@ -0,0 +1,109 @@
+package gateway
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/Jiei-S/boilerplate-clean-architecture/internal/domain/entity"
+	pkgErr "github.com/Jiei-S/boilerplate-clean-architecture/pkg/error"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testAPIKeyRepositoryContract runs the behavior every APIKeyRepository
+// adapter must share.
+func testAPIKeyRepositoryContract(t *testing.T, newAdapters adaptersFactory) {
+	ctx := context.Background()
+
+	t.Run("find by hash returns saved key", func(t *testing.T) {
+		repo := newAdapters(t).apiKeys
+		e, key := newTestAPIKey(t, "batch")
+		saved, err := repo.Save(ctx, e)
+		require.Nil(t, err)
+		assert.NotEmpty(t, saved.ID)
+		assert.False(t, saved.CreatedAt.IsZero())
+
+		found, err := repo.FindByHash(ctx, entity.HashAPIKey(key))
+		require.Nil(t, err)
+		assert.Equal(t, saved.ID, found.ID)
+		assert.Equal(t, "batch", found.Name)
+		assert.Equal(t, e.Prefix, found.Prefix)
+		assert.Equal(t, []string{"users:create", "users:list"}, found.Scopes)
+		assert.Equal(t, "admin", found.CreatedBy)
+		assert.Nil(t, found.LastUsedAt)
+		assert.False(t, found.Revoked())
+	})
+
+	t.Run("find by unknown hash is not found", func(t *testing.T) {
+		repo := newAdapters(t).apiKeys
+
+		_, err := repo.FindByHash(ctx, entity.HashAPIKey("ak_unknown"))
+		assertReason(t, err, pkgErr.CodeNotFound, entity.ReasonAPIKeyNotFound)
+	})
+
+	t.Run("list returns newest first", func(t *testing.T) {
+		repo := newAdapters(t).apiKeys
+		for i, name := range []string{"first", "second"} {
+			if i > 0 {
+				// Databases keep timestamps to the second.
+				time.Sleep(time.Second)
+			}
+			e, _ := newTestAPIKey(t, name)
+			_, err := repo.Save(ctx, e)
+			require.Nil(t, err)
+		}
+
+		keys, err := repo.List(ctx)
+		require.Nil(t, err)
+		require.Len(t, keys, 2)
+		assert.Equal(t, "second", keys[0].Name)
+		assert.Equal(t, "first", keys[1].Name)
+	})
+
+	t.Run("revoke marks key revoked once", func(t *testing.T) {
+		repo := newAdapters(t).apiKeys
+		e, key := newTestAPIKey(t, "batch")
+		saved, err := repo.Save(ctx, e)
+		require.Nil(t, err)
+
+		require.Nil(t, repo.Revoke(ctx, saved.ID))
+		found, err := repo.FindByHash(ctx, entity.HashAPIKey(key))
+		require.Nil(t, err)
+		require.True(t, found.Revoked())
+		revokedAt := *found.RevokedAt
+
+		require.Nil(t, repo.Revoke(ctx, saved.ID))
+		found, err = repo.FindByHash(ctx, entity.HashAPIKey(key))
+		require.Nil(t, err)
+		assert.True(t, revokedAt.Equal(*found.RevokedAt))
+	})
+
+	t.Run("revoke missing key is not found", func(t *testing.T) {
+		repo := newAdapters(t).apiKeys
+
+		err := repo.Revoke(ctx, "00000000-0000-0000-0000-000000000000")
+		assertReason(t, err, pkgErr.CodeNotFound, entity.ReasonAPIKeyNotFound)
+	})
+
+	t.Run("touch records last use", func(t *testing.T) {
+		repo := newAdapters(t).apiKeys
+		e, key := newTestAPIKey(t, "batch")
+		saved, err := repo.Save(ctx, e)
+		require.Nil(t, err)
+
+		require.Nil(t, repo.Touch(ctx, saved.ID))
+		found, err := repo.FindByHash(ctx, entity.HashAPIKey(key))
+		require.Nil(t, err)
+		if assert.NotNil(t, found.LastUsedAt) {
+			assert.WithinDuration(t, time.Now(), *found.LastUsedAt, 2*time.Second)
+		}
+	})
+}
+
+func newTestAPIKey(t *testing.T, name string) (*entity.APIKey, string) {
+	t.Helper()
+	e, key, err := entity.NewAPIKey(name, []string{"users:create", "users:list"}, "admin")
+	require.Nil(t, err)
+	return e, key
+}
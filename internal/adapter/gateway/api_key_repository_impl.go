@@ -0,0 +1,110 @@
+package gateway
+
+import (
+	"context"
+	"time"
+
+	"github.com/Jiei-S/boilerplate-clean-architecture/internal/domain/entity"
+	"github.com/Jiei-S/boilerplate-clean-architecture/internal/usecase"
+	pkgErr "github.com/Jiei-S/boilerplate-clean-architecture/pkg/error"
+	"github.com/Jiei-S/boilerplate-clean-architecture/pkg/tracing"
+	"github.com/uptrace/bun"
+)
+
+var _ usecase.APIKeyRepository = (*APIKeyRepositoryImpl)(nil)
+
+type APIKeyRepositoryImpl struct {
+	db *bun.DB
+}
+
+func (k *APIKeyRepositoryImpl) Save(ctx context.Context, entity *entity.APIKey) (_ *entity.APIKey, appErr *pkgErr.ApplicationError) {
+	ctx, span := tracing.Start(ctx, "APIKeyRepository.Save")
+	defer func() { tracing.End(span, appErr) }()
+
+	db := conn(ctx, k.db)
+
+	key := FromAPIKeyEntity(entity)
+	if _, err := db.NewInsert().Model(key).Exec(ctx); err != nil {
+		return nil, APIKeyRepositoryError(err, key.ID)
+	}
+	return key.ToEntity(), nil
+}
+
+func (k *APIKeyRepositoryImpl) FindByHash(ctx context.Context, hash string) (_ *entity.APIKey, appErr *pkgErr.ApplicationError) {
+	ctx, span := tracing.Start(ctx, "APIKeyRepository.FindByHash")
+	defer func() { tracing.End(span, appErr) }()
+
+	db := conn(ctx, k.db)
+
+	var key APIKey
+	if err := db.NewSelect().Model(&key).Where("key_hash = ?", hash).Scan(ctx); err != nil {
+		return nil, APIKeyRepositoryError(err, "")
+	}
+	return key.ToEntity(), nil
+}
+
+func (k *APIKeyRepositoryImpl) List(ctx context.Context) (_ []*entity.APIKey, appErr *pkgErr.ApplicationError) {
+	ctx, span := tracing.Start(ctx, "APIKeyRepository.List")
+	defer func() { tracing.End(span, appErr) }()
+
+	db := conn(ctx, k.db)
+
+	var keys []APIKey
+	if err := db.NewSelect().Model(&keys).Order("created_at DESC", "id DESC").Scan(ctx); err != nil {
+		return nil, RepositoryError(err)
+	}
+
+	entities := make([]*entity.APIKey, 0, len(keys))
+	for _, key := range keys {
+		entities = append(entities, key.ToEntity())
+	}
+	return entities, nil
+}
+
+func (k *APIKeyRepositoryImpl) Revoke(ctx context.Context, id string) (appErr *pkgErr.ApplicationError) {
+	ctx, span := tracing.Start(ctx, "APIKeyRepository.Revoke")
+	defer func() { tracing.End(span, appErr) }()
+
+	db := conn(ctx, k.db)
+
+	var key APIKey
+	if err := db.NewSelect().Model(&key).Where("id = ?", id).Scan(ctx); err != nil {
+		return APIKeyRepositoryError(err, id)
+	}
+	if key.RevokedAt != nil {
+		return nil
+	}
+	_, err := db.NewUpdate().Model((*APIKey)(nil)).
+		Set("revoked_at = ?", time.Now()).
+		Where("id = ?", id).
+		Where("revoked_at IS NULL").
+		Exec(ctx)
+	if err != nil {
+		return APIKeyRepositoryError(err, id)
+	}
+	return nil
+}
+
+func (k *APIKeyRepositoryImpl) Touch(ctx context.Context, id string) (appErr *pkgErr.ApplicationError) {
+	ctx, span := tracing.Start(ctx, "APIKeyRepository.Touch")
+	defer func() { tracing.End(span, appErr) }()
+
+	db := conn(ctx, k.db)
+
+	// A key deleted meanwhile has nothing to record, so the number of rows
+	// is not checked.
+	_, err := db.NewUpdate().Model((*APIKey)(nil)).
+		Set("last_used_at = ?", time.Now()).
+		Where("id = ?", id).
+		Exec(ctx)
+	if err != nil {
+		return APIKeyRepositoryError(err, id)
+	}
+	return nil
+}
+
+func NewAPIKeyRepository(db *bun.DB) usecase.APIKeyRepository {
+	return &APIKeyRepositoryImpl{
+		db: db,
+	}
+}
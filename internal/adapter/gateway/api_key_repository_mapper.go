@@ -0,0 +1,80 @@
+package gateway
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/Jiei-S/boilerplate-clean-architecture/internal/domain/entity"
+	pkgErr "github.com/Jiei-S/boilerplate-clean-architecture/pkg/error"
+	"github.com/google/uuid"
+	"github.com/uptrace/bun"
+)
+
+type APIKey struct {
+	bun.BaseModel `bun:"table:api_keys"`
+
+	ID     string `bun:"id,pk,type:varchar(36)"`
+	Name   string `bun:"name,notnull"`
+	Prefix string `bun:"prefix,notnull"`
+	Hash   string `bun:"key_hash,notnull"`
+	// Scopes are separated by spaces, as in OAuth.
+	Scopes     string     `bun:"scopes,notnull"`
+	CreatedBy  string     `bun:"created_by,notnull"`
+	CreatedAt  time.Time  `bun:"created_at,nullzero,notnull,default:current_timestamp"`
+	LastUsedAt *time.Time `bun:"last_used_at"`
+	RevokedAt  *time.Time `bun:"revoked_at"`
+}
+
+var _ bun.BeforeAppendModelHook = (*APIKey)(nil)
+
+func (k *APIKey) BeforeAppendModel(ctx context.Context, query bun.Query) error {
+	if _, ok := query.(*bun.InsertQuery); ok {
+		uuidObj, _ := uuid.NewUUID()
+		k.ID = uuidObj.String()
+		k.CreatedAt = time.Now()
+	}
+	return nil
+}
+
+func (k *APIKey) ToEntity() *entity.APIKey {
+	return &entity.APIKey{
+		ID:         k.ID,
+		Name:       k.Name,
+		Prefix:     k.Prefix,
+		Hash:       k.Hash,
+		Scopes:     strings.Fields(k.Scopes),
+		CreatedBy:  k.CreatedBy,
+		CreatedAt:  k.CreatedAt,
+		LastUsedAt: k.LastUsedAt,
+		RevokedAt:  k.RevokedAt,
+	}
+}
+
+func FromAPIKeyEntity(
+	entity *entity.APIKey,
+) *APIKey {
+	return &APIKey{
+		ID:         entity.ID,
+		Name:       entity.Name,
+		Prefix:     entity.Prefix,
+		Hash:       entity.Hash,
+		Scopes:     strings.Join(entity.Scopes, " "),
+		CreatedBy:  entity.CreatedBy,
+		CreatedAt:  entity.CreatedAt,
+		LastUsedAt: entity.LastUsedAt,
+		RevokedAt:  entity.RevokedAt,
+	}
+}
+
+// APIKeyRepositoryError is RepositoryError with the key the query was about.
+func APIKeyRepositoryError(err error, id string) *pkgErr.ApplicationError {
+	appErr := RepositoryError(err)
+	if appErr.Code() == pkgErr.CodeNotFound {
+		appErr = appErr.WithReason(entity.ReasonAPIKeyNotFound)
+		if id != "" {
+			appErr = appErr.WithDetail("apiKeyId", id)
+		}
+	}
+	return appErr
+}
@@ -0,0 +1,103 @@
+package gateway
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"sort"
+	"time"
+
+	"github.com/Jiei-S/boilerplate-clean-architecture/internal/domain/entity"
+	"github.com/Jiei-S/boilerplate-clean-architecture/internal/usecase"
+	pkgErr "github.com/Jiei-S/boilerplate-clean-architecture/pkg/error"
+	"github.com/google/uuid"
+)
+
+// errMemoryDuplicateKey stands in for the unique constraint on
+// api_keys (key_hash).
+var errMemoryDuplicateKey = errors.New("duplicate entry for api_keys (key_hash)")
+
+var _ usecase.APIKeyRepository = (*MemoryAPIKeyRepositoryImpl)(nil)
+
+// MemoryAPIKeyRepositoryImpl is an APIKeyRepository that keeps keys in a
+// MemoryStore.
+type MemoryAPIKeyRepositoryImpl struct {
+	store *MemoryStore
+}
+
+func (k *MemoryAPIKeyRepositoryImpl) Save(ctx context.Context, e *entity.APIKey) (*entity.APIKey, *pkgErr.ApplicationError) {
+	defer k.store.lock(ctx)()
+
+	key := *e
+	uuidObj, _ := uuid.NewUUID()
+	key.ID = uuidObj.String()
+	key.CreatedAt = time.Now()
+	for _, other := range k.store.apiKeys {
+		if other.Hash == key.Hash {
+			return nil, APIKeyRepositoryError(duplicateError(errMemoryDuplicateKey), key.ID)
+		}
+	}
+
+	k.store.apiKeys[key.ID] = key
+	return &key, nil
+}
+
+func (k *MemoryAPIKeyRepositoryImpl) FindByHash(ctx context.Context, hash string) (*entity.APIKey, *pkgErr.ApplicationError) {
+	defer k.store.lock(ctx)()
+
+	for _, key := range k.store.apiKeys {
+		if key.Hash == hash {
+			return &key, nil
+		}
+	}
+	return nil, APIKeyRepositoryError(sql.ErrNoRows, "")
+}
+
+func (k *MemoryAPIKeyRepositoryImpl) List(ctx context.Context) ([]*entity.APIKey, *pkgErr.ApplicationError) {
+	defer k.store.lock(ctx)()
+
+	entities := make([]*entity.APIKey, 0, len(k.store.apiKeys))
+	for _, key := range k.store.apiKeys {
+		key := key
+		entities = append(entities, &key)
+	}
+	sort.Slice(entities, func(i, j int) bool {
+		if c := entities[i].CreatedAt.Compare(entities[j].CreatedAt); c != 0 {
+			return c > 0
+		}
+		return entities[i].ID > entities[j].ID
+	})
+	return entities, nil
+}
+
+func (k *MemoryAPIKeyRepositoryImpl) Revoke(ctx context.Context, id string) *pkgErr.ApplicationError {
+	defer k.store.lock(ctx)()
+
+	key, ok := k.store.apiKeys[id]
+	if !ok {
+		return APIKeyRepositoryError(sql.ErrNoRows, id)
+	}
+	if key.RevokedAt == nil {
+		now := time.Now()
+		key.RevokedAt = &now
+		k.store.apiKeys[id] = key
+	}
+	return nil
+}
+
+func (k *MemoryAPIKeyRepositoryImpl) Touch(ctx context.Context, id string) *pkgErr.ApplicationError {
+	defer k.store.lock(ctx)()
+
+	if key, ok := k.store.apiKeys[id]; ok {
+		now := time.Now()
+		key.LastUsedAt = &now
+		k.store.apiKeys[id] = key
+	}
+	return nil
+}
+
+func NewMemoryAPIKeyRepository(store *MemoryStore) usecase.APIKeyRepository {
+	return &MemoryAPIKeyRepositoryImpl{
+		store: store,
+	}
+}
@@ -0,0 +1,86 @@
+package gateway
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/Jiei-S/boilerplate-clean-architecture/internal/domain/entity"
+	pkgErr "github.com/Jiei-S/boilerplate-clean-architecture/pkg/error"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testIdempotencyRepositoryContract runs the behavior every
+// IdempotencyRepository adapter must share.
+func testIdempotencyRepositoryContract(t *testing.T, newAdapters adaptersFactory) {
+	ctx := context.Background()
+
+	t.Run("find returns saved key of the subject", func(t *testing.T) {
+		repo := newAdapters(t).idempotencyKeys
+		saved, err := repo.Save(ctx, newTestIdempotencyKey(t, "key-1", "alice", time.Hour))
+		require.Nil(t, err)
+		assert.NotEmpty(t, saved.ID)
+		assert.False(t, saved.CreatedAt.IsZero())
+		require.Nil(t, repo.SaveResponse(ctx, saved.ID, []byte(`{"ID":"1"}`)))
+
+		found, err := repo.Find(ctx, "alice", "key-1")
+		require.Nil(t, err)
+		assert.Equal(t, saved.ID, found.ID)
+		assert.Equal(t, "fingerprint", found.Fingerprint)
+		assert.JSONEq(t, `{"ID":"1"}`, string(found.Response))
+		assert.False(t, found.Expired(time.Now()))
+
+		_, err = repo.Find(ctx, "bob", "key-1")
+		assertReason(t, err, pkgErr.CodeNotFound, pkgErr.ReasonNotFound)
+	})
+
+	t.Run("save of a taken key is in use", func(t *testing.T) {
+		repo := newAdapters(t).idempotencyKeys
+		_, err := repo.Save(ctx, newTestIdempotencyKey(t, "key-1", "alice", time.Hour))
+		require.Nil(t, err)
+
+		_, err = repo.Save(ctx, newTestIdempotencyKey(t, "key-1", "alice", time.Hour))
+		assertReason(t, err, pkgErr.CodeConflict, entity.ReasonIdempotencyKeyInUse)
+
+		// Subjects have their own keys.
+		_, err = repo.Save(ctx, newTestIdempotencyKey(t, "key-1", "bob", time.Hour))
+		assert.Nil(t, err)
+	})
+
+	t.Run("delete frees the key", func(t *testing.T) {
+		repo := newAdapters(t).idempotencyKeys
+		saved, err := repo.Save(ctx, newTestIdempotencyKey(t, "key-1", "alice", time.Hour))
+		require.Nil(t, err)
+
+		require.Nil(t, repo.Delete(ctx, saved.ID))
+		_, err = repo.Find(ctx, "alice", "key-1")
+		assertReason(t, err, pkgErr.CodeNotFound, pkgErr.ReasonNotFound)
+		_, err = repo.Save(ctx, newTestIdempotencyKey(t, "key-1", "alice", time.Hour))
+		assert.Nil(t, err)
+	})
+
+	t.Run("delete expired keeps live keys", func(t *testing.T) {
+		repo := newAdapters(t).idempotencyKeys
+		_, err := repo.Save(ctx, newTestIdempotencyKey(t, "expired", "alice", -time.Minute))
+		require.Nil(t, err)
+		_, err = repo.Save(ctx, newTestIdempotencyKey(t, "live", "alice", time.Hour))
+		require.Nil(t, err)
+
+		n, err := repo.DeleteExpired(ctx, time.Now())
+		require.Nil(t, err)
+		assert.Equal(t, 1, n)
+
+		_, err = repo.Find(ctx, "alice", "expired")
+		assertReason(t, err, pkgErr.CodeNotFound, pkgErr.ReasonNotFound)
+		_, err = repo.Find(ctx, "alice", "live")
+		assert.Nil(t, err)
+	})
+}
+
+func newTestIdempotencyKey(t *testing.T, key, subject string, ttl time.Duration) *entity.IdempotencyKey {
+	t.Helper()
+	e, err := entity.NewIdempotencyKey(key, subject, "fingerprint", ttl)
+	require.Nil(t, err)
+	return e
+}
@@ -0,0 +1,99 @@
+package gateway
+
+import (
+	"context"
+	"time"
+
+	"github.com/Jiei-S/boilerplate-clean-architecture/internal/domain/entity"
+	"github.com/Jiei-S/boilerplate-clean-architecture/internal/usecase"
+	pkgErr "github.com/Jiei-S/boilerplate-clean-architecture/pkg/error"
+	"github.com/Jiei-S/boilerplate-clean-architecture/pkg/tracing"
+	"github.com/uptrace/bun"
+)
+
+var _ usecase.IdempotencyRepository = (*IdempotencyRepositoryImpl)(nil)
+
+type IdempotencyRepositoryImpl struct {
+	db *bun.DB
+}
+
+func (k *IdempotencyRepositoryImpl) Find(ctx context.Context, subject, key string) (_ *entity.IdempotencyKey, appErr *pkgErr.ApplicationError) {
+	ctx, span := tracing.Start(ctx, "IdempotencyRepository.Find")
+	defer func() { tracing.End(span, appErr) }()
+
+	db := conn(ctx, k.db)
+
+	var record IdempotencyKey
+	err := db.NewSelect().Model(&record).
+		Where("subject = ?", subject).
+		Where("idempotency_key = ?", key).
+		Scan(ctx)
+	if err != nil {
+		return nil, IdempotencyRepositoryError(err)
+	}
+	return record.ToEntity(), nil
+}
+
+func (k *IdempotencyRepositoryImpl) Save(ctx context.Context, entity *entity.IdempotencyKey) (_ *entity.IdempotencyKey, appErr *pkgErr.ApplicationError) {
+	ctx, span := tracing.Start(ctx, "IdempotencyRepository.Save")
+	defer func() { tracing.End(span, appErr) }()
+
+	db := conn(ctx, k.db)
+
+	record := FromIdempotencyKeyEntity(entity)
+	if _, err := db.NewInsert().Model(record).Exec(ctx); err != nil {
+		return nil, IdempotencyRepositoryError(err)
+	}
+	return record.ToEntity(), nil
+}
+
+func (k *IdempotencyRepositoryImpl) SaveResponse(ctx context.Context, id string, response []byte) (appErr *pkgErr.ApplicationError) {
+	ctx, span := tracing.Start(ctx, "IdempotencyRepository.SaveResponse")
+	defer func() { tracing.End(span, appErr) }()
+
+	db := conn(ctx, k.db)
+
+	_, err := db.NewUpdate().Model((*IdempotencyKey)(nil)).
+		Set("response = ?", string(response)).
+		Where("id = ?", id).
+		Exec(ctx)
+	if err != nil {
+		return IdempotencyRepositoryError(err)
+	}
+	return nil
+}
+
+func (k *IdempotencyRepositoryImpl) Delete(ctx context.Context, id string) (appErr *pkgErr.ApplicationError) {
+	ctx, span := tracing.Start(ctx, "IdempotencyRepository.Delete")
+	defer func() { tracing.End(span, appErr) }()
+
+	db := conn(ctx, k.db)
+
+	if _, err := db.NewDelete().Model((*IdempotencyKey)(nil)).Where("id = ?", id).Exec(ctx); err != nil {
+		return IdempotencyRepositoryError(err)
+	}
+	return nil
+}
+
+func (k *IdempotencyRepositoryImpl) DeleteExpired(ctx context.Context, now time.Time) (_ int, appErr *pkgErr.ApplicationError) {
+	ctx, span := tracing.Start(ctx, "IdempotencyRepository.DeleteExpired")
+	defer func() { tracing.End(span, appErr) }()
+
+	db := conn(ctx, k.db)
+
+	res, err := db.NewDelete().Model((*IdempotencyKey)(nil)).Where("expires_at <= ?", now).Exec(ctx)
+	if err != nil {
+		return 0, IdempotencyRepositoryError(err)
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return 0, IdempotencyRepositoryError(err)
+	}
+	return int(n), nil
+}
+
+func NewIdempotencyRepository(db *bun.DB) usecase.IdempotencyRepository {
+	return &IdempotencyRepositoryImpl{
+		db: db,
+	}
+}
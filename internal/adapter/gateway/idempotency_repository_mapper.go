@@ -0,0 +1,71 @@
+package gateway
+
+import (
+	"context"
+	"time"
+
+	"github.com/Jiei-S/boilerplate-clean-architecture/internal/domain/entity"
+	pkgErr "github.com/Jiei-S/boilerplate-clean-architecture/pkg/error"
+	"github.com/google/uuid"
+	"github.com/uptrace/bun"
+)
+
+type IdempotencyKey struct {
+	bun.BaseModel `bun:"table:idempotency_keys"`
+
+	ID          string    `bun:"id,pk,type:varchar(36)"`
+	Key         string    `bun:"idempotency_key,notnull"`
+	Subject     string    `bun:"subject,notnull"`
+	Fingerprint string    `bun:"fingerprint,notnull"`
+	Response    string    `bun:"response,notnull"`
+	CreatedAt   time.Time `bun:"created_at,nullzero,notnull,default:current_timestamp"`
+	ExpiresAt   time.Time `bun:"expires_at,notnull"`
+}
+
+var _ bun.BeforeAppendModelHook = (*IdempotencyKey)(nil)
+
+func (k *IdempotencyKey) BeforeAppendModel(ctx context.Context, query bun.Query) error {
+	if _, ok := query.(*bun.InsertQuery); ok {
+		uuidObj, _ := uuid.NewUUID()
+		k.ID = uuidObj.String()
+		k.CreatedAt = time.Now()
+	}
+	return nil
+}
+
+func (k *IdempotencyKey) ToEntity() *entity.IdempotencyKey {
+	return &entity.IdempotencyKey{
+		ID:          k.ID,
+		Key:         k.Key,
+		Subject:     k.Subject,
+		Fingerprint: k.Fingerprint,
+		Response:    []byte(k.Response),
+		CreatedAt:   k.CreatedAt,
+		ExpiresAt:   k.ExpiresAt,
+	}
+}
+
+func FromIdempotencyKeyEntity(
+	entity *entity.IdempotencyKey,
+) *IdempotencyKey {
+	return &IdempotencyKey{
+		ID:          entity.ID,
+		Key:         entity.Key,
+		Subject:     entity.Subject,
+		Fingerprint: entity.Fingerprint,
+		Response:    string(entity.Response),
+		CreatedAt:   entity.CreatedAt,
+		ExpiresAt:   entity.ExpiresAt,
+	}
+}
+
+// IdempotencyRepositoryError is RepositoryError that tells a key taken by a
+// concurrent request.
+func IdempotencyRepositoryError(err error) *pkgErr.ApplicationError {
+	appErr := RepositoryError(err)
+	if appErr.Code() == pkgErr.CodeDuplicate {
+		return pkgErr.Wrap(err, "a request with the same Idempotency-Key is in progress", pkgErr.LevelWarn, pkgErr.CodeConflict).
+			WithReason(entity.ReasonIdempotencyKeyInUse)
+	}
+	return appErr
+}
@@ -0,0 +1,89 @@
+package gateway
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/Jiei-S/boilerplate-clean-architecture/internal/domain/entity"
+	"github.com/Jiei-S/boilerplate-clean-architecture/internal/usecase"
+	pkgErr "github.com/Jiei-S/boilerplate-clean-architecture/pkg/error"
+	"github.com/google/uuid"
+)
+
+// errMemoryDuplicateIdempotencyKey stands in for the unique constraint on
+// idempotency_keys (subject, idempotency_key).
+var errMemoryDuplicateIdempotencyKey = errors.New("duplicate entry for idempotency_keys (subject, idempotency_key)")
+
+var _ usecase.IdempotencyRepository = (*MemoryIdempotencyRepositoryImpl)(nil)
+
+// MemoryIdempotencyRepositoryImpl is an IdempotencyRepository that keeps
+// keys in a MemoryStore.
+type MemoryIdempotencyRepositoryImpl struct {
+	store *MemoryStore
+}
+
+func (k *MemoryIdempotencyRepositoryImpl) Find(ctx context.Context, subject, key string) (*entity.IdempotencyKey, *pkgErr.ApplicationError) {
+	defer k.store.lock(ctx)()
+
+	for _, record := range k.store.idempotencyKeys {
+		if record.Subject == subject && record.Key == key {
+			return &record, nil
+		}
+	}
+	return nil, IdempotencyRepositoryError(sql.ErrNoRows)
+}
+
+func (k *MemoryIdempotencyRepositoryImpl) Save(ctx context.Context, e *entity.IdempotencyKey) (*entity.IdempotencyKey, *pkgErr.ApplicationError) {
+	defer k.store.lock(ctx)()
+
+	record := *e
+	uuidObj, _ := uuid.NewUUID()
+	record.ID = uuidObj.String()
+	record.CreatedAt = time.Now()
+	for _, other := range k.store.idempotencyKeys {
+		if other.Subject == record.Subject && other.Key == record.Key {
+			return nil, IdempotencyRepositoryError(duplicateError(errMemoryDuplicateIdempotencyKey))
+		}
+	}
+
+	k.store.idempotencyKeys[record.ID] = record
+	return &record, nil
+}
+
+func (k *MemoryIdempotencyRepositoryImpl) SaveResponse(ctx context.Context, id string, response []byte) *pkgErr.ApplicationError {
+	defer k.store.lock(ctx)()
+
+	if record, ok := k.store.idempotencyKeys[id]; ok {
+		record.Response = response
+		k.store.idempotencyKeys[id] = record
+	}
+	return nil
+}
+
+func (k *MemoryIdempotencyRepositoryImpl) Delete(ctx context.Context, id string) *pkgErr.ApplicationError {
+	defer k.store.lock(ctx)()
+
+	delete(k.store.idempotencyKeys, id)
+	return nil
+}
+
+func (k *MemoryIdempotencyRepositoryImpl) DeleteExpired(ctx context.Context, now time.Time) (int, *pkgErr.ApplicationError) {
+	defer k.store.lock(ctx)()
+
+	var n int
+	for id, record := range k.store.idempotencyKeys {
+		if record.Expired(now) {
+			delete(k.store.idempotencyKeys, id)
+			n++
+		}
+	}
+	return n, nil
+}
+
+func NewMemoryIdempotencyRepository(store *MemoryStore) usecase.IdempotencyRepository {
+	return &MemoryIdempotencyRepositoryImpl{
+		store: store,
+	}
+}
@@ -0,0 +1,67 @@
+package gateway
+
+import (
+	"context"
+	"sync"
+
+	"github.com/Jiei-S/boilerplate-clean-architecture/internal/domain/entity"
+)
+
+type memoryTxKey struct{}
+
+// MemoryStore holds the data of the in-memory repositories. Transactions on
+// it are serialized, and a rolled back transaction restores the snapshot
+// taken when it began.
+type MemoryStore struct {
+	mu              sync.Mutex
+	users           map[string]entity.User
+	apiKeys         map[string]entity.APIKey
+	idempotencyKeys map[string]entity.IdempotencyKey
+}
+
+// memorySnapshot is the data of a MemoryStore at some point.
+type memorySnapshot struct {
+	users           map[string]entity.User
+	apiKeys         map[string]entity.APIKey
+	idempotencyKeys map[string]entity.IdempotencyKey
+}
+
+// lock gives the caller exclusive access to the store, unless ctx belongs to
+// a transaction that already has it.
+func (s *MemoryStore) lock(ctx context.Context) func() {
+	if _, ok := ctx.Value(memoryTxKey{}).(bool); ok {
+		return func() {}
+	}
+	s.mu.Lock()
+	return s.mu.Unlock
+}
+
+func (s *MemoryStore) snapshot() memorySnapshot {
+	users := make(map[string]entity.User, len(s.users))
+	for id, user := range s.users {
+		users[id] = user
+	}
+	apiKeys := make(map[string]entity.APIKey, len(s.apiKeys))
+	for id, key := range s.apiKeys {
+		apiKeys[id] = key
+	}
+	idempotencyKeys := make(map[string]entity.IdempotencyKey, len(s.idempotencyKeys))
+	for id, key := range s.idempotencyKeys {
+		idempotencyKeys[id] = key
+	}
+	return memorySnapshot{users: users, apiKeys: apiKeys, idempotencyKeys: idempotencyKeys}
+}
+
+func (s *MemoryStore) restore(snapshot memorySnapshot) {
+	s.users = snapshot.users
+	s.apiKeys = snapshot.apiKeys
+	s.idempotencyKeys = snapshot.idempotencyKeys
+}
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		users:           map[string]entity.User{},
+		apiKeys:         map[string]entity.APIKey{},
+		idempotencyKeys: map[string]entity.IdempotencyKey{},
+	}
+}
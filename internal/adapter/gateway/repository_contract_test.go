@@ -0,0 +1,97 @@
+package gateway
+
+import (
+	"context"
+	"log/slog"
+	"testing"
+
+	"github.com/Jiei-S/boilerplate-clean-architecture/internal/infrastructure/bun"
+	"github.com/Jiei-S/boilerplate-clean-architecture/internal/infrastructure/config"
+	"github.com/Jiei-S/boilerplate-clean-architecture/internal/usecase"
+	"github.com/stretchr/testify/require"
+	uptrace "github.com/uptrace/bun"
+)
+
+// adapters are the repositories of one implementation, and the Transactor
+// that goes with them, all backed by the same store.
+type adapters struct {
+	users           usecase.UserRepository
+	apiKeys         usecase.APIKeyRepository
+	idempotencyKeys usecase.IdempotencyRepository
+	transactor      usecase.Transactor
+}
+
+// adaptersFactory returns adapters backed by an empty store.
+type adaptersFactory func(t *testing.T) *adapters
+
+// repositorySuite runs the behavior every adapter of a repository must
+// share.
+type repositorySuite struct {
+	name string
+	run  func(t *testing.T, newAdapters adaptersFactory)
+}
+
+// TestRepositoryContracts runs every suite against every implementation.
+func TestRepositoryContracts(t *testing.T) {
+	implementations := []struct {
+		name        string
+		newAdapters adaptersFactory
+	}{
+		{
+			name: "memory",
+			newAdapters: func(t *testing.T) *adapters {
+				store := NewMemoryStore()
+				return &adapters{
+					users:           NewMemoryUserRepository(store),
+					apiKeys:         NewMemoryAPIKeyRepository(store),
+					idempotencyKeys: NewMemoryIdempotencyRepository(store),
+					transactor:      NewMemoryTransactor(store),
+				}
+			},
+		},
+		{
+			name: "sqlite",
+			newAdapters: func(t *testing.T) *adapters {
+				db := newTestDB(t)
+				return &adapters{
+					users:           NewUserRepository(db),
+					apiKeys:         NewAPIKeyRepository(db),
+					idempotencyKeys: NewIdempotencyRepository(db),
+					transactor:      NewTransactor(db, slog.Default()),
+				}
+			},
+		},
+	}
+	suites := []repositorySuite{
+		{name: "UserRepository", run: testUserRepositoryContract},
+		{name: "APIKeyRepository", run: testAPIKeyRepositoryContract},
+		{name: "IdempotencyRepository", run: testIdempotencyRepositoryContract},
+	}
+
+	for _, impl := range implementations {
+		t.Run(impl.name, func(t *testing.T) {
+			for _, suite := range suites {
+				t.Run(suite.name, func(t *testing.T) {
+					suite.run(t, impl.newAdapters)
+				})
+			}
+		})
+	}
+}
+
+// newTestDB returns an in-memory SQLite database with every migration
+// applied.
+func newTestDB(t *testing.T) *uptrace.DB {
+	t.Helper()
+	cfg := config.Default().Database
+	cfg.Driver, cfg.DSN = config.DriverSQLite, ":memory:"
+	db, closeDB, err := bun.NewDB(cfg)
+	require.NoError(t, err)
+	t.Cleanup(closeDB)
+
+	migrator, err := bun.NewMigrator(db)
+	require.NoError(t, err)
+	_, err = bun.Migrate(context.Background(), migrator, cfg.MigrationLockTimeout)
+	require.NoError(t, err)
+	return db
+}
@@ -0,0 +1,150 @@
+package gateway
+
+import (
+	"database/sql"
+	"errors"
+
+	pkgErr "github.com/Jiei-S/boilerplate-clean-architecture/pkg/error"
+	"github.com/go-sql-driver/mysql"
+	"github.com/uptrace/bun/driver/pgdriver"
+	"modernc.org/sqlite"
+	sqlite3 "modernc.org/sqlite/lib"
+)
+
+// MySQL server error numbers, see
+// https://dev.mysql.com/doc/mysql-errors/8.0/en/server-error-reference.html
+const (
+	mysqlErrDuplicateEntry  = 1062
+	mysqlErrLockWaitTimeout = 1205
+	mysqlErrDeadlock        = 1213
+	mysqlErrDataTooLong     = 1406
+	mysqlErrRowIsReferenced = 1451
+	mysqlErrNoReferencedRow = 1452
+)
+
+// PostgreSQL SQLSTATE codes, see
+// https://www.postgresql.org/docs/current/errcodes-appendix.html
+const (
+	pgErrStringDataRightTruncation = "22001"
+	pgErrForeignKeyViolation       = "23503"
+	pgErrUniqueViolation           = "23505"
+	pgErrSerializationFailure      = "40001"
+	pgErrDeadlockDetected          = "40P01"
+	pgErrLockNotAvailable          = "55P03"
+)
+
+const (
+	ReasonReferenceNotFound    pkgErr.Reason = "REFERENCE_NOT_FOUND"
+	ReasonStillReferenced      pkgErr.Reason = "STILL_REFERENCED"
+	ReasonForeignKeyViolation  pkgErr.Reason = "FOREIGN_KEY_VIOLATION"
+	ReasonValueTooLong         pkgErr.Reason = "VALUE_TOO_LONG"
+	ReasonDeadlock             pkgErr.Reason = "DEADLOCK"
+	ReasonLockWaitTimeout      pkgErr.Reason = "LOCK_WAIT_TIMEOUT"
+	ReasonSerializationFailure pkgErr.Reason = "SERIALIZATION_FAILURE"
+)
+
+// RepositoryError classifies err by the error of whichever database driver
+// produced it. Errors that an adapter already classified are returned as is.
+func RepositoryError(err error) *pkgErr.ApplicationError {
+	var classified *pkgErr.ApplicationError
+	if errors.As(err, &classified) {
+		return classified
+	}
+	if errors.Is(err, sql.ErrNoRows) {
+		return pkgErr.Wrap(err, "resource not found", pkgErr.LevelWarn, pkgErr.CodeNotFound)
+	}
+
+	var (
+		mysqlErr  *mysql.MySQLError
+		pgErr     pgdriver.Error
+		sqliteErr *sqlite.Error
+		appErr    *pkgErr.ApplicationError
+	)
+	switch {
+	case errors.As(err, &mysqlErr):
+		appErr = mysqlError(err, mysqlErr)
+	case errors.As(err, &pgErr):
+		appErr = postgresError(err, pgErr)
+	case errors.As(err, &sqliteErr):
+		appErr = sqliteError(err, sqliteErr)
+	}
+	if appErr != nil {
+		return appErr
+	}
+	return pkgErr.Wrap(err, "database error", pkgErr.LevelError, pkgErr.CodeInternalServerError)
+}
+
+func mysqlError(err error, mysqlErr *mysql.MySQLError) *pkgErr.ApplicationError {
+	switch mysqlErr.Number {
+	case mysqlErrDuplicateEntry:
+		return duplicateError(err)
+	case mysqlErrNoReferencedRow:
+		return pkgErr.Wrap(err, "referenced resource not found", pkgErr.LevelWarn, pkgErr.CodeConflict).
+			WithReason(ReasonReferenceNotFound)
+	case mysqlErrRowIsReferenced:
+		return pkgErr.Wrap(err, "resource is still referenced", pkgErr.LevelWarn, pkgErr.CodeConflict).
+			WithReason(ReasonStillReferenced)
+	case mysqlErrDataTooLong:
+		return valueTooLongError(err)
+	case mysqlErrDeadlock:
+		return deadlockError(err)
+	case mysqlErrLockWaitTimeout:
+		return lockWaitTimeoutError(err)
+	}
+	return nil
+}
+
+func postgresError(err error, pgErr pgdriver.Error) *pkgErr.ApplicationError {
+	switch pgErr.Field('C') {
+	case pgErrUniqueViolation:
+		return duplicateError(err)
+	case pgErrForeignKeyViolation:
+		return foreignKeyViolationError(err)
+	case pgErrStringDataRightTruncation:
+		return valueTooLongError(err)
+	case pgErrDeadlockDetected:
+		return deadlockError(err)
+	case pgErrSerializationFailure:
+		return pkgErr.Wrap(err, "transaction aborted by a concurrent update", pkgErr.LevelWarn, pkgErr.CodeUnavailable).
+			WithReason(ReasonSerializationFailure)
+	case pgErrLockNotAvailable:
+		return lockWaitTimeoutError(err)
+	}
+	return nil
+}
+
+func sqliteError(err error, sqliteErr *sqlite.Error) *pkgErr.ApplicationError {
+	switch sqliteErr.Code() {
+	case sqlite3.SQLITE_CONSTRAINT_UNIQUE, sqlite3.SQLITE_CONSTRAINT_PRIMARYKEY:
+		return duplicateError(err)
+	case sqlite3.SQLITE_CONSTRAINT_FOREIGNKEY:
+		return foreignKeyViolationError(err)
+	case sqlite3.SQLITE_BUSY, sqlite3.SQLITE_LOCKED:
+		return lockWaitTimeoutError(err)
+	}
+	return nil
+}
+
+func duplicateError(err error) *pkgErr.ApplicationError {
+	return pkgErr.Wrap(err, "resource already exists", pkgErr.LevelWarn, pkgErr.CodeDuplicate)
+}
+
+func foreignKeyViolationError(err error) *pkgErr.ApplicationError {
+	return pkgErr.Wrap(err, "foreign key constraint violated", pkgErr.LevelWarn, pkgErr.CodeConflict).
+		WithReason(ReasonForeignKeyViolation)
+}
+
+func valueTooLongError(err error) *pkgErr.ApplicationError {
+	return pkgErr.Wrap(err, "value too long", pkgErr.LevelWarn, pkgErr.CodeBadRequest).
+		WithReason(ReasonValueTooLong)
+}
+
+func deadlockError(err error) *pkgErr.ApplicationError {
+	return pkgErr.Wrap(err, "transaction aborted by a deadlock", pkgErr.LevelWarn, pkgErr.CodeUnavailable).
+		WithReason(ReasonDeadlock)
+}
+
+func lockWaitTimeoutError(err error) *pkgErr.ApplicationError {
+	return pkgErr.Wrap(err, "lock wait timeout exceeded", pkgErr.LevelWarn, pkgErr.CodeUnavailable).
+		WithReason(ReasonLockWaitTimeout)
+}
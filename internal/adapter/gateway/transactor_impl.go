@@ -0,0 +1,146 @@
+package gateway
+
+import (
+	"context"
+	"database/sql"
+	"log/slog"
+	"math/rand"
+	"time"
+
+	"github.com/Jiei-S/boilerplate-clean-architecture/internal/usecase"
+	pkgErr "github.com/Jiei-S/boilerplate-clean-architecture/pkg/error"
+	"github.com/uptrace/bun"
+)
+
+type txKey struct{}
+
+// RetryPolicy retries transactions that the database aborted as a whole,
+// which is safe because none of their writes took effect. Attempts are
+// spaced by a random delay of up to BaseDelay*2^(attempt-1), capped at
+// MaxDelay.
+type RetryPolicy struct {
+	MaxAttempts int
+	BaseDelay   time.Duration
+	MaxDelay    time.Duration
+}
+
+var DefaultRetryPolicy = RetryPolicy{
+	MaxAttempts: 3,
+	BaseDelay:   20 * time.Millisecond,
+	MaxDelay:    500 * time.Millisecond,
+}
+
+var retriableReasons = map[pkgErr.Reason]bool{
+	ReasonDeadlock:             true,
+	ReasonLockWaitTimeout:      true,
+	ReasonSerializationFailure: true,
+}
+
+func (p RetryPolicy) retriable(err *pkgErr.ApplicationError) bool {
+	return retriableReasons[err.Reason()]
+}
+
+func (p RetryPolicy) backoff(attempt int) time.Duration {
+	d := p.BaseDelay << (attempt - 1)
+	if d <= 0 || d > p.MaxDelay {
+		d = p.MaxDelay
+	}
+	return time.Duration(rand.Int63n(int64(d) + 1))
+}
+
+var _ usecase.Transactor = (*TransactorImpl)(nil)
+
+type TransactorImpl struct {
+	db          *bun.DB
+	retryPolicy RetryPolicy
+	logger      *slog.Logger
+	// sleep waits d before a retry, or less if ctx is done first.
+	sleep func(ctx context.Context, d time.Duration) error
+}
+
+// RunInTx retries fn according to the retry policy when it runs the
+// outermost transaction. A nested transaction cannot be retried on its own,
+// so its errors are left to the outermost one.
+func (t *TransactorImpl) RunInTx(
+	ctx context.Context,
+	opts *sql.TxOptions,
+	fn func(ctx context.Context) *pkgErr.ApplicationError,
+) *pkgErr.ApplicationError {
+	if tx, ok := ctx.Value(txKey{}).(bun.Tx); ok {
+		return runInTx(fn, func(run func(ctx context.Context, tx bun.Tx) error) error {
+			return tx.RunInTx(ctx, opts, run)
+		})
+	}
+
+	for attempt := 1; ; attempt++ {
+		err := runInTx(fn, func(run func(ctx context.Context, tx bun.Tx) error) error {
+			return t.db.RunInTx(ctx, opts, run)
+		})
+		if err == nil || !t.retryPolicy.retriable(err) {
+			if err == nil && attempt > 1 {
+				t.logger.InfoContext(ctx, "Transaction succeeded after retrying", "attempts", attempt)
+			}
+			return err
+		}
+		if attempt >= t.retryPolicy.MaxAttempts {
+			t.logger.WarnContext(ctx, "Transaction failed after retrying", "attempts", attempt, "error", err)
+			return err
+		}
+
+		delay := t.retryPolicy.backoff(attempt)
+		t.logger.InfoContext(ctx, "Retrying transaction",
+			"delay", delay, "attempt", attempt+1, "maxAttempts", t.retryPolicy.MaxAttempts, "error", err)
+		if t.sleep(ctx, delay) != nil {
+			return err
+		}
+	}
+}
+
+func sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+func runInTx(
+	fn func(ctx context.Context) *pkgErr.ApplicationError,
+	begin func(run func(ctx context.Context, tx bun.Tx) error) error,
+) *pkgErr.ApplicationError {
+	var appErr *pkgErr.ApplicationError
+	err := begin(func(ctx context.Context, tx bun.Tx) error {
+		if appErr = fn(context.WithValue(ctx, txKey{}, tx)); appErr != nil {
+			return appErr
+		}
+		return nil
+	})
+	if appErr != nil {
+		return appErr
+	}
+	if err != nil {
+		return RepositoryError(err)
+	}
+	return nil
+}
+
+// conn returns the transaction started by Transactor.RunInTx, or db when
+// ctx carries none.
+func conn(ctx context.Context, db *bun.DB) bun.IDB {
+	if tx, ok := ctx.Value(txKey{}).(bun.Tx); ok {
+		return tx
+	}
+	return db
+}
+
+func NewTransactor(db *bun.DB, logger *slog.Logger) usecase.Transactor {
+	return &TransactorImpl{
+		db:          db,
+		retryPolicy: DefaultRetryPolicy,
+		logger:      logger,
+		sleep:       sleep,
+	}
+}
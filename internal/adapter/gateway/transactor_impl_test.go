@@ -0,0 +1,116 @@
+package gateway
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	pkgErr "github.com/Jiei-S/boilerplate-clean-architecture/pkg/error"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTransactorRetries(t *testing.T) {
+	policy := RetryPolicy{MaxAttempts: 4, BaseDelay: 10 * time.Millisecond, MaxDelay: 25 * time.Millisecond}
+	newTransactor := func(t *testing.T) (*TransactorImpl, *[]time.Duration) {
+		var delays []time.Duration
+		return &TransactorImpl{
+			db:          newTestDB(t),
+			retryPolicy: policy,
+			logger:      slog.New(slog.NewTextHandler(io.Discard, nil)),
+			sleep: func(_ context.Context, d time.Duration) error {
+				delays = append(delays, d)
+				return nil
+			},
+		}, &delays
+	}
+	// failing returns a transaction that fails with reason for the first
+	// failures attempts, and counts its attempts in calls.
+	failing := func(reason pkgErr.Reason, failures int, calls *int) func(context.Context) *pkgErr.ApplicationError {
+		return func(context.Context) *pkgErr.ApplicationError {
+			*calls++
+			if *calls <= failures {
+				return pkgErr.NewApplicationError("aborted", pkgErr.LevelWarn, pkgErr.CodeUnavailable).WithReason(reason)
+			}
+			return nil
+		}
+	}
+
+	for _, reason := range []pkgErr.Reason{ReasonDeadlock, ReasonLockWaitTimeout, ReasonSerializationFailure} {
+		t.Run("retries "+string(reason), func(t *testing.T) {
+			tx, delays := newTransactor(t)
+			var calls int
+			err := tx.RunInTx(context.Background(), nil, failing(reason, 2, &calls))
+			assert.Nil(t, err)
+			assert.Equal(t, 3, calls)
+			assert.Len(t, *delays, 2)
+		})
+	}
+
+	t.Run("gives up after the last attempt", func(t *testing.T) {
+		tx, delays := newTransactor(t)
+		var calls int
+		err := tx.RunInTx(context.Background(), nil, failing(ReasonDeadlock, 10, &calls))
+		if assert.NotNil(t, err) {
+			assert.Equal(t, ReasonDeadlock, err.Reason())
+		}
+		assert.Equal(t, policy.MaxAttempts, calls)
+		assert.Len(t, *delays, policy.MaxAttempts-1)
+	})
+
+	t.Run("caps the backoff", func(t *testing.T) {
+		tx, delays := newTransactor(t)
+		var calls int
+		tx.RunInTx(context.Background(), nil, failing(ReasonDeadlock, 10, &calls))
+		bounds := []time.Duration{10 * time.Millisecond, 20 * time.Millisecond, 25 * time.Millisecond}
+		for i, d := range *delays {
+			assert.GreaterOrEqual(t, d, time.Duration(0))
+			assert.LessOrEqual(t, d, bounds[i], "delay %d", i+1)
+		}
+		for attempt := 1; attempt < 100; attempt++ {
+			assert.LessOrEqual(t, policy.backoff(attempt), policy.MaxDelay)
+		}
+	})
+
+	t.Run("does not retry other errors", func(t *testing.T) {
+		for _, reason := range []pkgErr.Reason{pkgErr.ReasonDuplicate, ReasonForeignKeyViolation, pkgErr.ReasonUnavailable} {
+			tx, delays := newTransactor(t)
+			var calls int
+			err := tx.RunInTx(context.Background(), nil, failing(reason, 10, &calls))
+			if assert.NotNil(t, err) {
+				assert.Equal(t, reason, err.Reason())
+			}
+			assert.Equal(t, 1, calls, reason)
+			assert.Empty(t, *delays, reason)
+		}
+	})
+
+	t.Run("stops retrying when the context is done", func(t *testing.T) {
+		tx, _ := newTransactor(t)
+		tx.sleep = func(context.Context, time.Duration) error {
+			return context.Canceled
+		}
+		var calls int
+		err := tx.RunInTx(context.Background(), nil, failing(ReasonDeadlock, 10, &calls))
+		if assert.NotNil(t, err) {
+			assert.Equal(t, ReasonDeadlock, err.Reason())
+		}
+		assert.Equal(t, 1, calls)
+	})
+
+	t.Run("leaves nested transactions to the outermost one", func(t *testing.T) {
+		tx, delays := newTransactor(t)
+		var outer, inner int
+		err := tx.RunInTx(context.Background(), nil, func(ctx context.Context) *pkgErr.ApplicationError {
+			outer++
+			return tx.RunInTx(ctx, nil, failing(ReasonDeadlock, 1, &inner))
+		})
+		assert.Nil(t, err)
+		// The savepoint is not retried on its own: the failure goes up and
+		// the whole transaction runs again.
+		assert.Equal(t, 2, outer)
+		assert.Equal(t, 2, inner)
+		assert.Len(t, *delays, 1)
+	})
+}
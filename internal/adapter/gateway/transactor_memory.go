@@ -0,0 +1,45 @@
+package gateway
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/Jiei-S/boilerplate-clean-architecture/internal/usecase"
+	pkgErr "github.com/Jiei-S/boilerplate-clean-architecture/pkg/error"
+)
+
+var _ usecase.Transactor = (*MemoryTransactorImpl)(nil)
+
+type MemoryTransactorImpl struct {
+	store *MemoryStore
+}
+
+// RunInTx ignores opts, as every transaction on the store is serializable.
+func (t *MemoryTransactorImpl) RunInTx(
+	ctx context.Context,
+	opts *sql.TxOptions,
+	fn func(ctx context.Context) *pkgErr.ApplicationError,
+) *pkgErr.ApplicationError {
+	unlock := t.store.lock(ctx)
+	defer unlock()
+
+	snapshot := t.store.snapshot()
+	done := false
+	defer func() {
+		if !done {
+			t.store.restore(snapshot)
+		}
+	}()
+
+	if err := fn(context.WithValue(ctx, memoryTxKey{}, true)); err != nil {
+		return err
+	}
+	done = true
+	return nil
+}
+
+func NewMemoryTransactor(store *MemoryStore) usecase.Transactor {
+	return &MemoryTransactorImpl{
+		store: store,
+	}
+}
@@ -0,0 +1,326 @@
+package gateway
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Jiei-S/boilerplate-clean-architecture/internal/domain/entity"
+	"github.com/Jiei-S/boilerplate-clean-architecture/internal/usecase"
+	pkgErr "github.com/Jiei-S/boilerplate-clean-architecture/pkg/error"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testUserRepositoryContract runs the behavior every UserRepository adapter
+// must share.
+func testUserRepositoryContract(t *testing.T, newAdapters adaptersFactory) {
+	ctx := context.Background()
+
+	t.Run("save generates ID and timestamps", func(t *testing.T) {
+		repo := newAdapters(t).users
+
+		saved, err := repo.Save(ctx, newTestUser(t, "taro", "yamada", 20))
+		require.Nil(t, err)
+		assert.NotEmpty(t, saved.ID)
+		assert.False(t, saved.CreatedAt.IsZero())
+		assert.False(t, saved.UpdatedAt.IsZero())
+
+		other, err := repo.Save(ctx, newTestUser(t, "hanako", "yamada", 20))
+		require.Nil(t, err)
+		assert.NotEqual(t, saved.ID, other.ID)
+	})
+
+	t.Run("find returns saved user", func(t *testing.T) {
+		repo := newAdapters(t).users
+		saved, err := repo.Save(ctx, newTestUser(t, "taro", "yamada", 20))
+		require.Nil(t, err)
+
+		found, err := repo.Find(ctx, saved.ID)
+		require.Nil(t, err)
+		assert.Equal(t, saved.ID, found.ID)
+		assert.Equal(t, "taro", found.FirstName)
+		assert.Equal(t, "yamada", found.LastName)
+		assert.Equal(t, int32(20), found.Age)
+		// Databases keep timestamps at a lower precision than time.Time.
+		assert.WithinDuration(t, saved.CreatedAt, found.CreatedAt, time.Second)
+	})
+
+	t.Run("find missing user is not found", func(t *testing.T) {
+		repo := newAdapters(t).users
+
+		_, err := repo.Find(ctx, "00000000-0000-0000-0000-000000000000")
+		assertReason(t, err, pkgErr.CodeNotFound, entity.ReasonUserNotFound)
+	})
+
+	t.Run("save duplicate name is duplicate", func(t *testing.T) {
+		repo := newAdapters(t).users
+		_, err := repo.Save(ctx, newTestUser(t, "taro", "yamada", 20))
+		require.Nil(t, err)
+
+		_, err = repo.Save(ctx, newTestUser(t, "taro", "yamada", 30))
+		assertReason(t, err, pkgErr.CodeDuplicate, entity.ReasonUserAlreadyExists)
+	})
+
+	t.Run("update changes user", func(t *testing.T) {
+		repo := newAdapters(t).users
+		saved, err := repo.Save(ctx, newTestUser(t, "taro", "yamada", 20))
+		require.Nil(t, err)
+
+		require.Nil(t, saved.Update("jiro", "yamada", 21))
+		updated, err := repo.Update(ctx, saved)
+		require.Nil(t, err)
+		assert.False(t, updated.UpdatedAt.Before(saved.CreatedAt))
+
+		found, err := repo.Find(ctx, saved.ID)
+		require.Nil(t, err)
+		assert.Equal(t, "jiro", found.FirstName)
+		assert.Equal(t, int32(21), found.Age)
+		assert.False(t, found.UpdatedAt.Before(found.CreatedAt))
+	})
+
+	t.Run("update without changes succeeds", func(t *testing.T) {
+		repo := newAdapters(t).users
+		saved, err := repo.Save(ctx, newTestUser(t, "taro", "yamada", 20))
+		require.Nil(t, err)
+
+		_, err = repo.Update(ctx, saved)
+		assert.Nil(t, err)
+	})
+
+	t.Run("update missing user is not found", func(t *testing.T) {
+		repo := newAdapters(t).users
+		user := newTestUser(t, "taro", "yamada", 20)
+		user.ID = "00000000-0000-0000-0000-000000000000"
+
+		_, err := repo.Update(ctx, user)
+		assertReason(t, err, pkgErr.CodeNotFound, entity.ReasonUserNotFound)
+	})
+
+	t.Run("update to duplicate name is duplicate", func(t *testing.T) {
+		repo := newAdapters(t).users
+		_, err := repo.Save(ctx, newTestUser(t, "taro", "yamada", 20))
+		require.Nil(t, err)
+		saved, err := repo.Save(ctx, newTestUser(t, "jiro", "yamada", 20))
+		require.Nil(t, err)
+
+		require.Nil(t, saved.Update("taro", "yamada", 20))
+		_, err = repo.Update(ctx, saved)
+		assertReason(t, err, pkgErr.CodeDuplicate, entity.ReasonUserAlreadyExists)
+	})
+
+	t.Run("delete removes user", func(t *testing.T) {
+		repo := newAdapters(t).users
+		saved, err := repo.Save(ctx, newTestUser(t, "taro", "yamada", 20))
+		require.Nil(t, err)
+
+		require.Nil(t, repo.Delete(ctx, saved.ID))
+		_, err = repo.Find(ctx, saved.ID)
+		assertReason(t, err, pkgErr.CodeNotFound, entity.ReasonUserNotFound)
+
+		err = repo.Delete(ctx, saved.ID)
+		assertReason(t, err, pkgErr.CodeNotFound, entity.ReasonUserNotFound)
+	})
+
+	t.Run("list filters users", func(t *testing.T) {
+		repo := newAdapters(t).users
+		saveTestUsers(t, repo, 5)
+
+		first := "user1"
+		users, err := repo.List(ctx, &usecase.UserCriteria{FirstName: &first, Limit: 10})
+		require.Nil(t, err)
+		assert.Equal(t, []string{"user1"}, firstNames(users))
+
+		last := "yamada"
+		minAge, maxAge := int32(21), int32(23)
+		users, err = repo.List(ctx, &usecase.UserCriteria{
+			LastName: &last,
+			MinAge:   &minAge,
+			MaxAge:   &maxAge,
+			Sort:     usecase.UserSort{Field: usecase.UserSortAge},
+			Limit:    10,
+		})
+		require.Nil(t, err)
+		assert.Equal(t, []string{"user1", "user2", "user3"}, firstNames(users))
+	})
+
+	t.Run("list paginates in sort order", func(t *testing.T) {
+		repo := newAdapters(t).users
+		saveTestUsers(t, repo, 5)
+
+		for _, sort := range []usecase.UserSort{
+			{Field: usecase.UserSortCreatedAt},
+			{Field: usecase.UserSortCreatedAt, Desc: true},
+			{Field: usecase.UserSortFirstName},
+			{Field: usecase.UserSortAge, Desc: true},
+		} {
+			var seen []string
+			criteria := &usecase.UserCriteria{Sort: sort, Limit: 2}
+			for {
+				users, err := repo.List(ctx, criteria)
+				require.Nil(t, err)
+				seen = append(seen, firstNames(users)...)
+				if len(users) < criteria.Limit {
+					break
+				}
+				last := users[len(users)-1]
+				criteria.After = testCursor(last, sort)
+			}
+
+			require.Len(t, seen, 5, "sort %s", sort)
+			switch sort.Field {
+			case usecase.UserSortFirstName:
+				assert.Equal(t, []string{"user0", "user1", "user2", "user3", "user4"}, seen)
+			case usecase.UserSortAge:
+				assert.Equal(t, []string{"user4", "user3", "user2", "user1", "user0"}, seen)
+			default:
+				assert.ElementsMatch(t, []string{"user0", "user1", "user2", "user3", "user4"}, seen)
+			}
+		}
+	})
+
+	t.Run("transaction commits", func(t *testing.T) {
+		a := newAdapters(t)
+		repo, transactor := a.users, a.transactor
+
+		var id string
+		err := transactor.RunInTx(ctx, nil, func(ctx context.Context) *pkgErr.ApplicationError {
+			saved, err := repo.Save(ctx, newTestUser(t, "taro", "yamada", 20))
+			if err != nil {
+				return err
+			}
+			id = saved.ID
+			return nil
+		})
+		require.Nil(t, err)
+
+		_, err = repo.Find(ctx, id)
+		assert.Nil(t, err)
+	})
+
+	t.Run("transaction rolls back on error", func(t *testing.T) {
+		a := newAdapters(t)
+		repo, transactor := a.users, a.transactor
+
+		var id string
+		err := transactor.RunInTx(ctx, nil, func(ctx context.Context) *pkgErr.ApplicationError {
+			saved, err := repo.Save(ctx, newTestUser(t, "taro", "yamada", 20))
+			if err != nil {
+				return err
+			}
+			id = saved.ID
+			return pkgErr.NewApplicationError("abort", pkgErr.LevelInfo, pkgErr.CodeConflict)
+		})
+		assertReason(t, err, pkgErr.CodeConflict, pkgErr.ReasonConflict)
+
+		_, err = repo.Find(ctx, id)
+		assertReason(t, err, pkgErr.CodeNotFound, entity.ReasonUserNotFound)
+	})
+
+	t.Run("nested transaction rolls back to savepoint", func(t *testing.T) {
+		a := newAdapters(t)
+		repo, transactor := a.users, a.transactor
+
+		var outer, inner string
+		err := transactor.RunInTx(ctx, nil, func(ctx context.Context) *pkgErr.ApplicationError {
+			saved, err := repo.Save(ctx, newTestUser(t, "taro", "yamada", 20))
+			if err != nil {
+				return err
+			}
+			outer = saved.ID
+
+			err = transactor.RunInTx(ctx, nil, func(ctx context.Context) *pkgErr.ApplicationError {
+				saved, err := repo.Save(ctx, newTestUser(t, "jiro", "yamada", 20))
+				if err != nil {
+					return err
+				}
+				inner = saved.ID
+				return pkgErr.NewApplicationError("abort", pkgErr.LevelInfo, pkgErr.CodeConflict)
+			})
+			assertReason(t, err, pkgErr.CodeConflict, pkgErr.ReasonConflict)
+			return nil
+		})
+		require.Nil(t, err)
+
+		_, err = repo.Find(ctx, outer)
+		assert.Nil(t, err)
+		_, err = repo.Find(ctx, inner)
+		assertReason(t, err, pkgErr.CodeNotFound, entity.ReasonUserNotFound)
+	})
+
+	t.Run("concurrent saves", func(t *testing.T) {
+		repo := newAdapters(t).users
+
+		const n = 20
+		var wg sync.WaitGroup
+		errs := make(chan *pkgErr.ApplicationError, n)
+		for i := 0; i < n; i++ {
+			user := newTestUser(t, "taro", "yamada", int32(i))
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				_, err := repo.Save(ctx, user)
+				errs <- err
+			}()
+		}
+		wg.Wait()
+		close(errs)
+
+		saved := 0
+		for err := range errs {
+			if err == nil {
+				saved++
+				continue
+			}
+			assertReason(t, err, pkgErr.CodeDuplicate, entity.ReasonUserAlreadyExists)
+		}
+		assert.Equal(t, 1, saved)
+	})
+}
+
+func newTestUser(t *testing.T, firstName, lastName string, age int32) *entity.User {
+	t.Helper()
+	user, err := entity.NewUser(firstName, lastName, age)
+	require.Nil(t, err)
+	return user
+}
+
+// saveTestUsers saves users user0 yamada (20) to user<n-1> yamada (20+n-1).
+func saveTestUsers(t *testing.T, repo usecase.UserRepository, n int) {
+	t.Helper()
+	for i := 0; i < n; i++ {
+		_, err := repo.Save(context.Background(), newTestUser(t, fmt.Sprintf("user%d", i), "yamada", int32(20+i)))
+		require.Nil(t, err)
+	}
+}
+
+func testCursor(user *entity.User, sort usecase.UserSort) *usecase.UserCursor {
+	cursor := &usecase.UserCursor{CreatedAt: user.CreatedAt, ID: user.ID}
+	switch sort.Field {
+	case usecase.UserSortFirstName:
+		cursor.Value = user.FirstName
+	case usecase.UserSortLastName:
+		cursor.Value = user.LastName
+	case usecase.UserSortAge:
+		cursor.Value = user.Age
+	}
+	return cursor
+}
+
+func firstNames(users []*entity.User) []string {
+	names := make([]string, 0, len(users))
+	for _, user := range users {
+		names = append(names, user.FirstName)
+	}
+	return names
+}
+
+func assertReason(t *testing.T, err *pkgErr.ApplicationError, code pkgErr.ErrorCode, reason pkgErr.Reason) {
+	t.Helper()
+	if assert.NotNil(t, err) {
+		assert.Equal(t, code, err.Code())
+		assert.Equal(t, reason, err.Reason())
+	}
+}
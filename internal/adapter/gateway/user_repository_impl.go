@@ -2,42 +2,131 @@ package gateway
 
 import (
 	"context"
+	"database/sql"
+	"strings"
 
 	"github.com/Jiei-S/boilerplate-clean-architecture/internal/domain/entity"
 	"github.com/Jiei-S/boilerplate-clean-architecture/internal/usecase"
 	pkgErr "github.com/Jiei-S/boilerplate-clean-architecture/pkg/error"
+	"github.com/Jiei-S/boilerplate-clean-architecture/pkg/tracing"
 	"github.com/uptrace/bun"
 )
 
-type TxKey string
-
-const TX_KEY TxKey = "xxxxx"
-
 var _ usecase.UserRepository = (*UserRepositoryImpl)(nil)
 
 type UserRepositoryImpl struct {
+	db *bun.DB
 }
 
-func (u *UserRepositoryImpl) Save(ctx context.Context, entity *entity.User) (*entity.User, *pkgErr.ApplicationError) {
-	tx := ctx.Value(TX_KEY).(*bun.Tx)
+func (u *UserRepositoryImpl) Save(ctx context.Context, entity *entity.User) (_ *entity.User, appErr *pkgErr.ApplicationError) {
+	ctx, span := tracing.Start(ctx, "UserRepository.Save")
+	defer func() { tracing.End(span, appErr) }()
+
+	db := conn(ctx, u.db)
 
 	user := FromEntity(entity)
-	if _, err := tx.NewInsert().Model(user).Exec(ctx); err != nil {
-		return nil, RepositoryError(err)
+	if _, err := db.NewInsert().Model(user).Exec(ctx); err != nil {
+		return nil, UserRepositoryError(err, user.ID)
 	}
 	return user.ToEntity(), nil
 }
 
-func (u *UserRepositoryImpl) Find(ctx context.Context, id string) (*entity.User, *pkgErr.ApplicationError) {
-	tx := ctx.Value(TX_KEY).(*bun.Tx)
+func (u *UserRepositoryImpl) Find(ctx context.Context, id string) (_ *entity.User, appErr *pkgErr.ApplicationError) {
+	ctx, span := tracing.Start(ctx, "UserRepository.Find")
+	defer func() { tracing.End(span, appErr) }()
+
+	db := conn(ctx, u.db)
 
 	var user User
-	if err := tx.NewSelect().Model(&user).Where("id = ?", id).Scan(ctx); err != nil {
+	if err := db.NewSelect().Model(&user).Where("id = ?", id).Scan(ctx); err != nil {
+		return nil, UserRepositoryError(err, id)
+	}
+	return user.ToEntity(), nil
+}
+
+func (u *UserRepositoryImpl) List(ctx context.Context, criteria *usecase.UserCriteria) (_ []*entity.User, appErr *pkgErr.ApplicationError) {
+	ctx, span := tracing.Start(ctx, "UserRepository.List")
+	defer func() { tracing.End(span, appErr) }()
+
+	db := conn(ctx, u.db)
+
+	var users []User
+	q := db.NewSelect().Model(&users)
+	if criteria.FirstName != nil {
+		q = q.Where("first_name = ?", *criteria.FirstName)
+	}
+	if criteria.LastName != nil {
+		q = q.Where("last_name = ?", *criteria.LastName)
+	}
+	if criteria.MinAge != nil {
+		q = q.Where("age >= ?", *criteria.MinAge)
+	}
+	if criteria.MaxAge != nil {
+		q = q.Where("age <= ?", *criteria.MaxAge)
+	}
+
+	columns, order, op := sortColumns(criteria.Sort)
+	if after := criteria.After; after != nil {
+		values := []interface{}{after.CreatedAt, after.ID}
+		if len(columns) > len(values) {
+			values = append([]interface{}{after.Value}, values...)
+		}
+		q = q.Where("(?) "+op+" (?)", bun.Safe(strings.Join(columns, ", ")), bun.In(values))
+	}
+	for _, column := range columns {
+		q = q.OrderExpr("? "+order, bun.Ident(column))
+	}
+
+	if err := q.Limit(criteria.Limit).Scan(ctx); err != nil {
 		return nil, RepositoryError(err)
 	}
+
+	entities := make([]*entity.User, 0, len(users))
+	for _, user := range users {
+		entities = append(entities, user.ToEntity())
+	}
+	return entities, nil
+}
+
+func (u *UserRepositoryImpl) Update(ctx context.Context, entity *entity.User) (_ *entity.User, appErr *pkgErr.ApplicationError) {
+	ctx, span := tracing.Start(ctx, "UserRepository.Update")
+	defer func() { tracing.End(span, appErr) }()
+
+	db := conn(ctx, u.db)
+
+	user := FromEntity(entity)
+	result, err := db.NewUpdate().Model(user).Column("first_name", "last_name", "age", "updated_at").WherePK().Exec(ctx)
+	if err != nil {
+		return nil, UserRepositoryError(err, user.ID)
+	}
+	if n, err := result.RowsAffected(); err != nil {
+		return nil, UserRepositoryError(err, user.ID)
+	} else if n == 0 {
+		return nil, UserRepositoryError(sql.ErrNoRows, user.ID)
+	}
 	return user.ToEntity(), nil
 }
 
-func NewUserRepository() usecase.UserRepository {
-	return &UserRepositoryImpl{}
+func (u *UserRepositoryImpl) Delete(ctx context.Context, id string) (appErr *pkgErr.ApplicationError) {
+	ctx, span := tracing.Start(ctx, "UserRepository.Delete")
+	defer func() { tracing.End(span, appErr) }()
+
+	db := conn(ctx, u.db)
+
+	result, err := db.NewDelete().Model((*User)(nil)).Where("id = ?", id).Exec(ctx)
+	if err != nil {
+		return UserRepositoryError(err, id)
+	}
+	if n, err := result.RowsAffected(); err != nil {
+		return UserRepositoryError(err, id)
+	} else if n == 0 {
+		return UserRepositoryError(sql.ErrNoRows, id)
+	}
+	return nil
+}
+
+func NewUserRepository(db *bun.DB) usecase.UserRepository {
+	return &UserRepositoryImpl{
+		db: db,
+	}
 }
@@ -2,17 +2,17 @@ package gateway
 
 import (
 	"context"
-	"database/sql"
 	"time"
 
 	"github.com/Jiei-S/boilerplate-clean-architecture/internal/domain/entity"
+	"github.com/Jiei-S/boilerplate-clean-architecture/internal/usecase"
 	pkgErr "github.com/Jiei-S/boilerplate-clean-architecture/pkg/error"
 	"github.com/google/uuid"
 	"github.com/uptrace/bun"
 )
 
 type User struct {
-	ID        string    `bun:"id,pk,type:uuid,default:gen_random_uuid()"`
+	ID        string    `bun:"id,pk,type:varchar(36)"`
 	FirstName string    `bun:"first_name,notnull"`
 	LastName  string    `bun:"last_name,notnull"`
 	Age       int32     `bun:"age,notnull"`
@@ -27,6 +27,11 @@ func (u *User) BeforeAppendModel(ctx context.Context, query bun.Query) error {
 	case *bun.InsertQuery:
 		uuidObj, _ := uuid.NewUUID()
 		u.ID = uuidObj.String()
+		u.CreatedAt = time.Now()
+		u.UpdatedAt = u.CreatedAt
+		return nil
+	case *bun.UpdateQuery:
+		u.UpdatedAt = time.Now()
 		return nil
 	}
 	return nil
@@ -56,11 +61,34 @@ func FromEntity(
 	}
 }
 
-func RepositoryError(err error) *pkgErr.ApplicationError {
-	switch err {
-	case sql.ErrNoRows:
-		return pkgErr.NewApplicationError(err.Error(), pkgErr.LevelWarn, pkgErr.CodeNotFound)
-	default:
-		return pkgErr.NewApplicationError(err.Error(), pkgErr.LevelError, pkgErr.CodeInternalServerError)
+var userSortColumns = map[usecase.UserSortField]string{
+	usecase.UserSortFirstName: "first_name",
+	usecase.UserSortLastName:  "last_name",
+	usecase.UserSortAge:       "age",
+}
+
+// sortColumns returns the keyset columns for sort, which always end with
+// (created_at, id) so that the order is total, along with the ORDER BY
+// direction and the operator that selects rows after a cursor.
+func sortColumns(sort usecase.UserSort) ([]string, string, string) {
+	columns := []string{"created_at", "id"}
+	if column, ok := userSortColumns[sort.Field]; ok {
+		columns = append([]string{column}, columns...)
+	}
+	if sort.Desc {
+		return columns, "DESC", "<"
+	}
+	return columns, "ASC", ">"
+}
+
+// UserRepositoryError is RepositoryError with the user the query was about.
+func UserRepositoryError(err error, id string) *pkgErr.ApplicationError {
+	appErr := RepositoryError(err)
+	switch appErr.Code() {
+	case pkgErr.CodeNotFound:
+		appErr = appErr.WithReason(entity.ReasonUserNotFound).WithDetail("userId", id)
+	case pkgErr.CodeDuplicate:
+		appErr = appErr.WithReason(entity.ReasonUserAlreadyExists)
 	}
+	return appErr
 }
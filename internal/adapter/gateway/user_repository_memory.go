@@ -0,0 +1,176 @@
+package gateway
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/Jiei-S/boilerplate-clean-architecture/internal/domain/entity"
+	"github.com/Jiei-S/boilerplate-clean-architecture/internal/usecase"
+	pkgErr "github.com/Jiei-S/boilerplate-clean-architecture/pkg/error"
+	"github.com/google/uuid"
+)
+
+// errMemoryDuplicate stands in for the unique constraint on
+// users (first_name, last_name).
+var errMemoryDuplicate = errors.New("duplicate entry for users (first_name, last_name)")
+
+var _ usecase.UserRepository = (*MemoryUserRepositoryImpl)(nil)
+
+// MemoryUserRepositoryImpl is a UserRepository that keeps users in a
+// MemoryStore, so that the application runs without a database.
+type MemoryUserRepositoryImpl struct {
+	store *MemoryStore
+}
+
+func (u *MemoryUserRepositoryImpl) Save(ctx context.Context, e *entity.User) (*entity.User, *pkgErr.ApplicationError) {
+	defer u.store.lock(ctx)()
+
+	user := *e
+	uuidObj, _ := uuid.NewUUID()
+	user.ID = uuidObj.String()
+	user.CreatedAt = time.Now()
+	user.UpdatedAt = user.CreatedAt
+	if u.duplicates(&user) {
+		return nil, UserRepositoryError(duplicateError(errMemoryDuplicate), user.ID)
+	}
+
+	u.store.users[user.ID] = user
+	return &user, nil
+}
+
+func (u *MemoryUserRepositoryImpl) Find(ctx context.Context, id string) (*entity.User, *pkgErr.ApplicationError) {
+	defer u.store.lock(ctx)()
+
+	user, ok := u.store.users[id]
+	if !ok {
+		return nil, UserRepositoryError(sql.ErrNoRows, id)
+	}
+	return &user, nil
+}
+
+func (u *MemoryUserRepositoryImpl) List(ctx context.Context, criteria *usecase.UserCriteria) ([]*entity.User, *pkgErr.ApplicationError) {
+	defer u.store.lock(ctx)()
+
+	key := memorySortKey(criteria.Sort)
+	var after []interface{}
+	if criteria.After != nil {
+		after = []interface{}{criteria.After.Value, criteria.After.CreatedAt, criteria.After.ID}
+	}
+
+	entities := make([]*entity.User, 0, len(u.store.users))
+	for _, user := range u.store.users {
+		user := user
+		switch {
+		case criteria.FirstName != nil && user.FirstName != *criteria.FirstName,
+			criteria.LastName != nil && user.LastName != *criteria.LastName,
+			criteria.MinAge != nil && user.Age < *criteria.MinAge,
+			criteria.MaxAge != nil && user.Age > *criteria.MaxAge,
+			after != nil && compareKeys(key(&user), after, criteria.Sort) <= 0:
+			continue
+		}
+		entities = append(entities, &user)
+	}
+
+	sort.Slice(entities, func(i, j int) bool {
+		return compareKeys(key(entities[i]), key(entities[j]), criteria.Sort) < 0
+	})
+	if len(entities) > criteria.Limit {
+		entities = entities[:criteria.Limit]
+	}
+	return entities, nil
+}
+
+func (u *MemoryUserRepositoryImpl) Update(ctx context.Context, e *entity.User) (*entity.User, *pkgErr.ApplicationError) {
+	defer u.store.lock(ctx)()
+
+	current, ok := u.store.users[e.ID]
+	if !ok {
+		return nil, UserRepositoryError(sql.ErrNoRows, e.ID)
+	}
+
+	user := *e
+	user.CreatedAt = current.CreatedAt
+	user.UpdatedAt = time.Now()
+	if u.duplicates(&user) {
+		return nil, UserRepositoryError(duplicateError(errMemoryDuplicate), user.ID)
+	}
+
+	u.store.users[user.ID] = user
+	return &user, nil
+}
+
+func (u *MemoryUserRepositoryImpl) Delete(ctx context.Context, id string) *pkgErr.ApplicationError {
+	defer u.store.lock(ctx)()
+
+	if _, ok := u.store.users[id]; !ok {
+		return UserRepositoryError(sql.ErrNoRows, id)
+	}
+	delete(u.store.users, id)
+	return nil
+}
+
+func (u *MemoryUserRepositoryImpl) duplicates(user *entity.User) bool {
+	for _, other := range u.store.users {
+		if other.ID != user.ID && other.FirstName == user.FirstName && other.LastName == user.LastName {
+			return true
+		}
+	}
+	return false
+}
+
+// memorySortKey returns the keyset of a user in the order of sort, the
+// in-memory counterpart of sortColumns.
+func memorySortKey(sort usecase.UserSort) func(u *entity.User) []interface{} {
+	return func(u *entity.User) []interface{} {
+		var value interface{}
+		switch sort.Field {
+		case usecase.UserSortFirstName:
+			value = u.FirstName
+		case usecase.UserSortLastName:
+			value = u.LastName
+		case usecase.UserSortAge:
+			value = u.Age
+		}
+		return []interface{}{value, u.CreatedAt, u.ID}
+	}
+}
+
+func compareKeys(a, b []interface{}, sort usecase.UserSort) int {
+	for i := range a {
+		if c := compareValues(a[i], b[i]); c != 0 {
+			if sort.Desc {
+				return -c
+			}
+			return c
+		}
+	}
+	return 0
+}
+
+func compareValues(a, b interface{}) int {
+	switch a := a.(type) {
+	case string:
+		return strings.Compare(a, b.(string))
+	case int32:
+		b := b.(int32)
+		switch {
+		case a < b:
+			return -1
+		case a > b:
+			return 1
+		}
+	case time.Time:
+		return a.Compare(b.(time.Time))
+	}
+	return 0
+}
+
+func NewMemoryUserRepository(store *MemoryStore) usecase.UserRepository {
+	return &MemoryUserRepositoryImpl{
+		store: store,
+	}
+}
@@ -0,0 +1,88 @@
+package entity
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	pkgErr "github.com/Jiei-S/boilerplate-clean-architecture/pkg/error"
+)
+
+const (
+	APIKeyNameMaxLength = 100
+	// apiKeyPrefix marks API keys, so that leaked keys are easy to find.
+	apiKeyPrefix = "ak_"
+	// apiKeyDisplayLength is how much of a key is kept in the clear to tell
+	// keys apart, e.g. ak_3fJ8kQ2z.
+	apiKeyDisplayLength = len(apiKeyPrefix) + 8
+	apiKeySecretBytes   = 32
+)
+
+const (
+	ReasonAPIKeyNotFound pkgErr.Reason = "API_KEY_NOT_FOUND"
+)
+
+// APIKey authenticates a service. Only the hash of the key is kept; the key
+// itself is shown once, when the APIKey is created.
+type APIKey struct {
+	ID     string
+	Name   string
+	Prefix string
+	Hash   string
+	Scopes []string
+	// CreatedBy is the subject of the principal that created the key.
+	CreatedBy  string
+	CreatedAt  time.Time
+	LastUsedAt *time.Time
+	RevokedAt  *time.Time
+}
+
+// NewAPIKey generates an API key granting scopes, and returns it along with
+// the key in the clear.
+func NewAPIKey(name string, scopes []string, createdBy string) (*APIKey, string, *pkgErr.ApplicationError) {
+	var fields []pkgErr.FieldError
+	if strings.TrimSpace(name) == "" {
+		fields = append(fields, pkgErr.FieldError{Field: "name", Message: "must not be empty"})
+	} else if utf8.RuneCountInString(name) > APIKeyNameMaxLength {
+		fields = append(fields, pkgErr.FieldError{
+			Field:   "name",
+			Message: fmt.Sprintf("must be at most %d characters", APIKeyNameMaxLength),
+		})
+	}
+	if len(scopes) == 0 {
+		fields = append(fields, pkgErr.FieldError{Field: "scopes", Message: "must not be empty"})
+	}
+	if len(fields) > 0 {
+		return nil, "", pkgErr.NewValidationError(fields)
+	}
+
+	secret := make([]byte, apiKeySecretBytes)
+	if _, err := rand.Read(secret); err != nil {
+		return nil, "", pkgErr.Wrap(err, "failed to generate API key", pkgErr.LevelError, pkgErr.CodeInternalServerError)
+	}
+	key := apiKeyPrefix + base64.RawURLEncoding.EncodeToString(secret)
+
+	return &APIKey{
+		Name:      name,
+		Prefix:    key[:apiKeyDisplayLength],
+		Hash:      HashAPIKey(key),
+		Scopes:    scopes,
+		CreatedBy: createdBy,
+	}, key, nil
+}
+
+// HashAPIKey returns the hash that an API key is looked up by. Keys are
+// random enough that a fast hash is as good as a slow one.
+func HashAPIKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
+
+func (k *APIKey) Revoked() bool {
+	return k.RevokedAt != nil
+}
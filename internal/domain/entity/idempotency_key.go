@@ -0,0 +1,72 @@
+package entity
+
+import (
+	"fmt"
+	"time"
+
+	pkgErr "github.com/Jiei-S/boilerplate-clean-architecture/pkg/error"
+)
+
+const IdempotencyKeyMaxLength = 255
+
+const (
+	ReasonIdempotencyKeyReused pkgErr.Reason = "IDEMPOTENCY_KEY_REUSED"
+	ReasonIdempotencyKeyInUse  pkgErr.Reason = "IDEMPOTENCY_KEY_IN_USE"
+)
+
+// IdempotencyKey remembers the response to the first request that a client
+// sent with a key, so that retries with the same key get the same response
+// instead of repeating the request.
+type IdempotencyKey struct {
+	ID  string
+	Key string
+	// Subject is the subject of the principal that sent the request, so
+	// that clients cannot see each other's responses.
+	Subject string
+	// Fingerprint identifies the request, to tell a retry from another
+	// request with the same key.
+	Fingerprint string
+	Response    []byte
+	CreatedAt   time.Time
+	ExpiresAt   time.Time
+}
+
+// NewIdempotencyKey reserves key for the request of subject with
+// fingerprint until ttl has passed. Its response is saved once the request
+// succeeded.
+func NewIdempotencyKey(key, subject, fingerprint string, ttl time.Duration) (*IdempotencyKey, *pkgErr.ApplicationError) {
+	if err := ValidateIdempotencyKey(key); err != nil {
+		return nil, err
+	}
+	return &IdempotencyKey{
+		Key:         key,
+		Subject:     subject,
+		Fingerprint: fingerprint,
+		ExpiresAt:   time.Now().Add(ttl),
+	}, nil
+}
+
+// ValidateIdempotencyKey checks a key sent in an Idempotency-Key header.
+func ValidateIdempotencyKey(key string) *pkgErr.ApplicationError {
+	if key == "" || len(key) > IdempotencyKeyMaxLength {
+		return pkgErr.NewValidationError([]pkgErr.FieldError{{
+			Field:   "Idempotency-Key",
+			Message: fmt.Sprintf("must be 1 to %d characters", IdempotencyKeyMaxLength),
+		}})
+	}
+	return nil
+}
+
+func (k *IdempotencyKey) Expired(now time.Time) bool {
+	return !now.Before(k.ExpiresAt)
+}
+
+// Replayable reports whether the response to k may be returned for a
+// request with fingerprint, or else the key was reused for another request.
+func (k *IdempotencyKey) Replayable(fingerprint string) *pkgErr.ApplicationError {
+	if k.Fingerprint != fingerprint {
+		return pkgErr.NewApplicationError("Idempotency-Key was already used for a different request", pkgErr.LevelWarn, pkgErr.CodeUnprocessable).
+			WithReason(ReasonIdempotencyKeyReused)
+	}
+	return nil
+}
@@ -1,6 +1,24 @@
 package entity
 
-import "time"
+import (
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	pkgErr "github.com/Jiei-S/boilerplate-clean-architecture/pkg/error"
+)
+
+const (
+	UserNameMaxLength = 50
+	UserMinAge        = 0
+	UserMaxAge        = 150
+)
+
+const (
+	ReasonUserNotFound      pkgErr.Reason = "USER_NOT_FOUND"
+	ReasonUserAlreadyExists pkgErr.Reason = "USER_ALREADY_EXISTS"
+)
 
 type User struct {
 	ID        string
@@ -10,3 +28,47 @@ type User struct {
 	CreatedAt time.Time
 	UpdatedAt time.Time
 }
+
+func NewUser(firstName, lastName string, age int32) (*User, *pkgErr.ApplicationError) {
+	u := &User{}
+	if err := u.Update(firstName, lastName, age); err != nil {
+		return nil, err
+	}
+	return u, nil
+}
+
+// Update replaces the attributes of u, leaving it untouched if any of them
+// is invalid.
+func (u *User) Update(firstName, lastName string, age int32) *pkgErr.ApplicationError {
+	var fields []pkgErr.FieldError
+	if msg := validateName(firstName); msg != "" {
+		fields = append(fields, pkgErr.FieldError{Field: "firstName", Message: msg})
+	}
+	if msg := validateName(lastName); msg != "" {
+		fields = append(fields, pkgErr.FieldError{Field: "lastName", Message: msg})
+	}
+	if age < UserMinAge || age > UserMaxAge {
+		fields = append(fields, pkgErr.FieldError{
+			Field:   "age",
+			Message: fmt.Sprintf("must be between %d and %d", UserMinAge, UserMaxAge),
+		})
+	}
+	if len(fields) > 0 {
+		return pkgErr.NewValidationError(fields)
+	}
+
+	u.FirstName = firstName
+	u.LastName = lastName
+	u.Age = age
+	return nil
+}
+
+func validateName(name string) string {
+	if strings.TrimSpace(name) == "" {
+		return "must not be empty"
+	}
+	if utf8.RuneCountInString(name) > UserNameMaxLength {
+		return fmt.Sprintf("must be at most %d characters", UserNameMaxLength)
+	}
+	return ""
+}
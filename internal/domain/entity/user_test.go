@@ -0,0 +1,94 @@
+package entity
+
+import (
+	"strings"
+	"testing"
+
+	pkgErr "github.com/Jiei-S/boilerplate-clean-architecture/pkg/error"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewUser(t *testing.T) {
+	emptyName := "must not be empty"
+	longName := "must be at most 50 characters"
+	ageRange := "must be between 0 and 150"
+
+	tests := []struct {
+		name      string
+		firstName string
+		lastName  string
+		age       int32
+		want      []pkgErr.FieldError
+	}{
+		{name: "valid", firstName: "Taro", lastName: "Yamada", age: 20},
+		{name: "youngest", firstName: "Taro", lastName: "Yamada", age: 0},
+		{name: "oldest", firstName: "Taro", lastName: "Yamada", age: 150},
+		{name: "longest name", firstName: strings.Repeat("a", 50), lastName: "Yamada", age: 20},
+		{name: "longest multibyte name", firstName: strings.Repeat("太", 50), lastName: "Yamada", age: 20},
+		{
+			name: "empty first name", firstName: "", lastName: "Yamada", age: 20,
+			want: []pkgErr.FieldError{{Field: "firstName", Message: emptyName}},
+		},
+		{
+			name: "blank last name", firstName: "Taro", lastName: " \t\n", age: 20,
+			want: []pkgErr.FieldError{{Field: "lastName", Message: emptyName}},
+		},
+		{
+			name: "too long name", firstName: strings.Repeat("a", 51), lastName: "Yamada", age: 20,
+			want: []pkgErr.FieldError{{Field: "firstName", Message: longName}},
+		},
+		{
+			name: "too long multibyte name", firstName: "Taro", lastName: strings.Repeat("山", 51), age: 20,
+			want: []pkgErr.FieldError{{Field: "lastName", Message: longName}},
+		},
+		{
+			name: "negative age", firstName: "Taro", lastName: "Yamada", age: -1,
+			want: []pkgErr.FieldError{{Field: "age", Message: ageRange}},
+		},
+		{
+			name: "too old", firstName: "Taro", lastName: "Yamada", age: 151,
+			want: []pkgErr.FieldError{{Field: "age", Message: ageRange}},
+		},
+		{
+			name: "every field invalid", firstName: "", lastName: strings.Repeat("a", 51), age: 200,
+			want: []pkgErr.FieldError{
+				{Field: "firstName", Message: emptyName},
+				{Field: "lastName", Message: longName},
+				{Field: "age", Message: ageRange},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			user, err := NewUser(tt.firstName, tt.lastName, tt.age)
+			if tt.want == nil {
+				require.Nil(t, err)
+				assert.Equal(t, &User{FirstName: tt.firstName, LastName: tt.lastName, Age: tt.age}, user)
+				return
+			}
+			require.NotNil(t, err)
+			assert.Nil(t, user)
+			assert.Equal(t, pkgErr.CodeBadRequest, err.Code())
+			assert.Equal(t, pkgErr.ReasonValidationFailed, err.Reason())
+			assert.Equal(t, tt.want, err.Fields())
+		})
+	}
+}
+
+func TestUserUpdate(t *testing.T) {
+	user, err := NewUser("Taro", "Yamada", 20)
+	require.Nil(t, err)
+
+	require.Nil(t, user.Update("Hanako", "Suzuki", 30))
+	assert.Equal(t, "Hanako", user.FirstName)
+	assert.Equal(t, "Suzuki", user.LastName)
+	assert.Equal(t, int32(30), user.Age)
+
+	// An invalid update leaves every attribute untouched, even valid ones.
+	err = user.Update("Jiro", "", 30)
+	require.NotNil(t, err)
+	assert.Equal(t, []pkgErr.FieldError{{Field: "lastName", Message: "must not be empty"}}, err.Fields())
+	assert.Equal(t, "Hanako", user.FirstName)
+	assert.Equal(t, "Suzuki", user.LastName)
+}
@@ -0,0 +1,37 @@
+package auth
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+
+	"github.com/Jiei-S/boilerplate-clean-architecture/internal/infrastructure/config"
+	usecase "github.com/Jiei-S/boilerplate-clean-architecture/internal/usecase"
+	"gopkg.in/yaml.v3"
+)
+
+// policyFile is the declarative form of usecase.Policy.
+type policyFile struct {
+	Roles map[string][]usecase.Action `yaml:"roles"`
+	Self  []usecase.Action            `yaml:"self"`
+}
+
+// LoadPolicy reads the authorization policy of the policy file.
+func LoadPolicy(cfg config.AuthConfig) (*usecase.Policy, error) {
+	b, err := os.ReadFile(cfg.PolicyFile)
+	if err != nil {
+		return nil, fmt.Errorf("policy: %w", err)
+	}
+	var f policyFile
+	dec := yaml.NewDecoder(bytes.NewReader(b))
+	dec.KnownFields(true)
+	if err := dec.Decode(&f); err != nil {
+		return nil, fmt.Errorf("policy %s: %w", cfg.PolicyFile, err)
+	}
+
+	policy, err := usecase.NewPolicy(f.Roles, f.Self)
+	if err != nil {
+		return nil, fmt.Errorf("policy %s: %w", cfg.PolicyFile, err)
+	}
+	return policy, nil
+}
@@ -0,0 +1,14 @@
+package auth
+
+import (
+	"testing"
+
+	"github.com/Jiei-S/boilerplate-clean-architecture/internal/infrastructure/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoadPolicy(t *testing.T) {
+	// The policy shipped with the repository must load.
+	_, err := LoadPolicy(config.AuthConfig{PolicyFile: "../../../configs/policy.yaml"})
+	assert.NoError(t, err)
+}
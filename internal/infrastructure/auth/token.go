@@ -0,0 +1,32 @@
+package auth
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/Jiei-S/boilerplate-clean-architecture/internal/infrastructure/config"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// Sign mints a token for subject that expires after ttl, signed with the
+// HMAC secret of cfg. It serves development and tests; in production,
+// tokens are issued by the identity provider.
+func Sign(cfg config.AuthConfig, subject string, roles, scopes []string, ttl time.Duration) (string, error) {
+	if cfg.HMACSecret == "" {
+		return "", errors.New("tokens can only be signed with an HMAC secret")
+	}
+	now := time.Now()
+	claims := Claims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			Subject:   subject,
+			Issuer:    cfg.Issuer,
+			Audience:  jwt.ClaimStrings{cfg.Audience},
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(now.Add(ttl)),
+		},
+		Roles: roles,
+		Scope: strings.Join(scopes, " "),
+	}
+	return jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte(cfg.HMACSecret))
+}
@@ -0,0 +1,80 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/Jiei-S/boilerplate-clean-architecture/internal/infrastructure/config"
+	usecase "github.com/Jiei-S/boilerplate-clean-architecture/internal/usecase"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+var (
+	hmacMethods = []string{"HS256", "HS384", "HS512"}
+	jwksMethods = []string{"RS256", "RS384", "RS512", "PS256", "PS384", "PS512", "ES256", "ES384", "ES512"}
+)
+
+// Claims are the claims of a bearer token. Roles is a custom claim, and
+// Scope holds space-separated scopes as in RFC 8693.
+type Claims struct {
+	jwt.RegisteredClaims
+	Roles []string `json:"roles,omitempty"`
+	Scope string   `json:"scope,omitempty"`
+}
+
+// Verifier checks the signature, issuer, audience and expiry of bearer
+// tokens. Tokens must carry an expiry, and the algorithm is restricted to
+// the kind of key configured, so that a token cannot pick its own key.
+type Verifier struct {
+	parser *jwt.Parser
+	key    jwt.Keyfunc
+}
+
+// Verify returns the principal asserted by token.
+func (v *Verifier) Verify(_ context.Context, token string) (*usecase.Principal, error) {
+	var claims Claims
+	if _, err := v.parser.ParseWithClaims(token, &claims, v.key); err != nil {
+		return nil, err
+	}
+	if claims.Subject == "" {
+		return nil, errors.New("token has no subject")
+	}
+	return &usecase.Principal{
+		Subject: claims.Subject,
+		Roles:   claims.Roles,
+		Scopes:  strings.Fields(claims.Scope),
+	}, nil
+}
+
+func NewVerifier(cfg config.AuthConfig) (*Verifier, error) {
+	var (
+		methods []string
+		key     jwt.Keyfunc
+	)
+	if cfg.HMACSecret != "" {
+		methods = hmacMethods
+		secret := []byte(cfg.HMACSecret)
+		key = func(*jwt.Token) (interface{}, error) {
+			return secret, nil
+		}
+	} else {
+		keys, err := loadJWKS(cfg.JWKSFile)
+		if err != nil {
+			return nil, err
+		}
+		methods = jwksMethods
+		key = keys.lookup
+	}
+
+	return &Verifier{
+		parser: jwt.NewParser(
+			jwt.WithValidMethods(methods),
+			jwt.WithIssuer(cfg.Issuer),
+			jwt.WithAudience(cfg.Audience),
+			jwt.WithExpirationRequired(),
+			jwt.WithLeeway(cfg.Leeway),
+		),
+		key: key,
+	}, nil
+}
@@ -0,0 +1,180 @@
+package auth
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/Jiei-S/boilerplate-clean-architecture/internal/infrastructure/config"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const secret = "test-secret"
+
+func hmacConfig() config.AuthConfig {
+	return config.AuthConfig{
+		HMACSecret: secret,
+		Issuer:     "test-issuer",
+		Audience:   "test-audience",
+	}
+}
+
+func validClaims() Claims {
+	return Claims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			Subject:   "user-1",
+			Issuer:    "test-issuer",
+			Audience:  jwt.ClaimStrings{"test-audience"},
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour)),
+		},
+		Roles: []string{"admin"},
+		Scope: "users:read users:write",
+	}
+}
+
+func mint(t *testing.T, method jwt.SigningMethod, claims Claims, key interface{}, kid string) string {
+	t.Helper()
+	token := jwt.NewWithClaims(method, claims)
+	if kid != "" {
+		token.Header["kid"] = kid
+	}
+	s, err := token.SignedString(key)
+	require.NoError(t, err)
+	return s
+}
+
+func TestVerifierHMAC(t *testing.T) {
+	v, err := NewVerifier(hmacConfig())
+	require.NoError(t, err)
+
+	tests := []struct {
+		name   string
+		claims func(c *Claims)
+		key    string
+		valid  bool
+	}{
+		{name: "valid", claims: func(*Claims) {}, key: secret, valid: true},
+		{name: "wrong issuer", claims: func(c *Claims) { c.Issuer = "other" }, key: secret},
+		{name: "wrong audience", claims: func(c *Claims) { c.Audience = jwt.ClaimStrings{"other"} }, key: secret},
+		{name: "expired", claims: func(c *Claims) { c.ExpiresAt = jwt.NewNumericDate(time.Now().Add(-time.Hour)) }, key: secret},
+		{name: "no expiry", claims: func(c *Claims) { c.ExpiresAt = nil }, key: secret},
+		{name: "not yet valid", claims: func(c *Claims) { c.NotBefore = jwt.NewNumericDate(time.Now().Add(time.Hour)) }, key: secret},
+		{name: "no subject", claims: func(c *Claims) { c.Subject = "" }, key: secret},
+		{name: "wrong key", claims: func(*Claims) {}, key: "other-secret"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			claims := validClaims()
+			tt.claims(&claims)
+			token := mint(t, jwt.SigningMethodHS256, claims, []byte(tt.key), "")
+
+			p, err := v.Verify(context.Background(), token)
+			if !tt.valid {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, "user-1", p.Subject)
+			assert.Equal(t, []string{"admin"}, p.Roles)
+			assert.Equal(t, []string{"users:read", "users:write"}, p.Scopes)
+		})
+	}
+}
+
+func TestVerifierLeeway(t *testing.T) {
+	cfg := hmacConfig()
+	cfg.Leeway = time.Minute
+	v, err := NewVerifier(cfg)
+	require.NoError(t, err)
+
+	claims := validClaims()
+	claims.ExpiresAt = jwt.NewNumericDate(time.Now().Add(-30 * time.Second))
+	_, err = v.Verify(context.Background(), mint(t, jwt.SigningMethodHS256, claims, []byte(secret), ""))
+	assert.NoError(t, err)
+}
+
+func TestVerifierRejectsUnsignedTokens(t *testing.T) {
+	v, err := NewVerifier(hmacConfig())
+	require.NoError(t, err)
+
+	token := mint(t, jwt.SigningMethodNone, validClaims(), jwt.UnsafeAllowNoneSignatureType, "")
+	_, err = v.Verify(context.Background(), token)
+	assert.Error(t, err)
+}
+
+func TestVerifierJWKS(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	otherKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	enc := base64.RawURLEncoding
+	set := map[string]interface{}{
+		"keys": []map[string]string{
+			{
+				"kty": "RSA",
+				"kid": "rsa-1",
+				"use": "sig",
+				"n":   enc.EncodeToString(rsaKey.N.Bytes()),
+				"e":   enc.EncodeToString(big.NewInt(int64(rsaKey.E)).Bytes()),
+			},
+			{
+				"kty": "EC",
+				"kid": "ec-1",
+				"crv": "P-256",
+				"x":   enc.EncodeToString(ecKey.X.FillBytes(make([]byte, 32))),
+				"y":   enc.EncodeToString(ecKey.Y.FillBytes(make([]byte, 32))),
+			},
+		},
+	}
+	b, err := json.Marshal(set)
+	require.NoError(t, err)
+	file := filepath.Join(t.TempDir(), "jwks.json")
+	require.NoError(t, os.WriteFile(file, b, 0o600))
+
+	v, err := NewVerifier(config.AuthConfig{
+		JWKSFile: file,
+		Issuer:   "test-issuer",
+		Audience: "test-audience",
+	})
+	require.NoError(t, err)
+
+	tests := []struct {
+		name   string
+		method jwt.SigningMethod
+		key    interface{}
+		kid    string
+		valid  bool
+	}{
+		{name: "RSA", method: jwt.SigningMethodRS256, key: rsaKey, kid: "rsa-1", valid: true},
+		{name: "EC", method: jwt.SigningMethodES256, key: ecKey, kid: "ec-1", valid: true},
+		{name: "unknown kid", method: jwt.SigningMethodRS256, key: rsaKey, kid: "rsa-2"},
+		{name: "no kid with several keys", method: jwt.SigningMethodRS256, key: rsaKey},
+		{name: "key of another kid", method: jwt.SigningMethodRS256, key: otherKey, kid: "rsa-1"},
+		{name: "HMAC", method: jwt.SigningMethodHS256, key: []byte(secret), kid: "rsa-1"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, err := v.Verify(context.Background(), mint(t, tt.method, validClaims(), tt.key, tt.kid))
+			if !tt.valid {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, "user-1", p.Subject)
+		})
+	}
+}
@@ -1,48 +1,154 @@
 package bun
 
 import (
+	"context"
 	"database/sql"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"time"
 
-	"github.com/caarlos0/env"
 	"github.com/uptrace/bun"
 	"github.com/uptrace/bun/dialect/mysqldialect"
+	"github.com/uptrace/bun/dialect/pgdialect"
+	"github.com/uptrace/bun/dialect/sqlitedialect"
+	"github.com/uptrace/bun/driver/pgdriver"
 
+	"github.com/Jiei-S/boilerplate-clean-architecture/internal/infrastructure/config"
 	"github.com/go-sql-driver/mysql"
+	_ "modernc.org/sqlite"
 )
 
-type DBEnv struct {
-	Name      string `env:"MYSQL_DATABASE"`
-	User      string `env:"MYSQL_USER"`
-	Password  string `env:"MYSQL_PASSWORD"`
-	Address   string `env:"MYSQL_HOST"`
-	Collation string `env:"MYSQL_COLLATION"`
-}
+// NewDB opens the database selected by cfg and waits until it is reachable.
+// The returned function closes the pool once its connections are released.
+func NewDB(cfg config.DatabaseConfig) (*bun.DB, func(), error) {
+	var (
+		db  *bun.DB
+		err error
+	)
+	switch cfg.Driver {
+	case config.DriverMySQL:
+		db, err = newMySQL(cfg)
+	case config.DriverPostgres:
+		db, err = newPostgres(cfg)
+	case config.DriverSQLite:
+		db, err = newSQLite(cfg.DSN)
+	default:
+		err = fmt.Errorf("driver %q has no database", cfg.Driver)
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	if cfg.Driver != config.DriverSQLite {
+		setPool(db.DB, cfg.Pool)
+	}
 
-func NewDB() *bun.DB {
-	cfg := DBEnv{}
-	if err := env.Parse(&cfg); err != nil {
-		panic(err)
+	closeDB := func() {
+		if err := db.Close(); err != nil {
+			slog.Error("Failed to close database", "error", err)
+		}
+	}
+	if err := ping(db, cfg.Ping); err != nil {
+		closeDB()
+		return nil, nil, fmt.Errorf("connect to %s database: %w", cfg.Driver, err)
 	}
+	return db, closeDB, nil
+}
+
+func newMySQL(cfg config.DatabaseConfig) (*bun.DB, error) {
+	c := mysql.NewConfig()
+	c.User = cfg.MySQL.User
+	c.Passwd = cfg.MySQL.Password
+	c.Net = "tcp"
+	c.Addr = cfg.MySQL.Host
+	c.DBName = cfg.MySQL.Database
+	c.Collation = cfg.MySQL.Collation
+	c.ParseTime = true
+	c.AllowNativePasswords = true
+	// Report matched rather than changed rows, as the other drivers do.
+	c.ClientFoundRows = true
+	c.Timeout = cfg.DialTimeout
+	c.ReadTimeout = cfg.ReadTimeout
+	c.WriteTimeout = cfg.WriteTimeout
 
-	c := mysql.Config{
-		User:                 cfg.User,
-		Passwd:               cfg.Password,
-		Net:                  "tcp",
-		Addr:                 cfg.Address,
-		DBName:               cfg.Name,
-		Collation:            cfg.Collation,
-		ParseTime:            true,
-		AllowNativePasswords: true,
+	tlsConfig, err := newTLSConfig(cfg.TLS)
+	if err != nil {
+		return nil, err
 	}
+	c.TLS = tlsConfig
 
-	_db, err := sql.Open("mysql", c.FormatDSN())
+	connector, err := mysql.NewConnector(c)
 	if err != nil {
-		panic(err)
+		return nil, err
+	}
+	return bun.NewDB(sql.OpenDB(connector), mysqldialect.New()), nil
+}
+
+func newPostgres(cfg config.DatabaseConfig) (*bun.DB, error) {
+	opts := []pgdriver.Option{pgdriver.WithDSN(cfg.DSN)}
+	if cfg.DialTimeout > 0 {
+		opts = append(opts, pgdriver.WithDialTimeout(cfg.DialTimeout))
 	}
-	if err = _db.Ping(); err != nil {
-		panic(err)
+	if cfg.ReadTimeout > 0 {
+		opts = append(opts, pgdriver.WithReadTimeout(cfg.ReadTimeout))
 	}
+	if cfg.WriteTimeout > 0 {
+		opts = append(opts, pgdriver.WithWriteTimeout(cfg.WriteTimeout))
+	}
+	if cfg.TLS.Mode != "" {
+		tlsConfig, err := newTLSConfig(cfg.TLS)
+		if err != nil {
+			return nil, err
+		}
+		if tlsConfig != nil && tlsConfig.ServerName == "" {
+			if u, err := url.Parse(cfg.DSN); err == nil {
+				tlsConfig.ServerName = u.Hostname()
+			}
+		}
+		opts = append(opts, pgdriver.WithTLSConfig(tlsConfig))
+	}
+
+	return bun.NewDB(sql.OpenDB(pgdriver.NewConnector(opts...)), pgdialect.New()), nil
+}
+
+func newSQLite(dsn string) (*bun.DB, error) {
+	_db, err := sql.Open("sqlite", dsn)
+	if err != nil {
+		return nil, err
+	}
+	// SQLite allows a single writer at a time, and every connection to an
+	// in-memory database opens a database of its own.
+	_db.SetMaxOpenConns(1)
+	return bun.NewDB(_db, sqlitedialect.New()), nil
+}
+
+func setPool(db *sql.DB, cfg config.PoolConfig) {
+	db.SetMaxOpenConns(cfg.MaxOpenConns)
+	db.SetMaxIdleConns(cfg.MaxIdleConns)
+	db.SetConnMaxLifetime(cfg.ConnMaxLifetime)
+	db.SetConnMaxIdleTime(cfg.ConnMaxIdleTime)
+}
+
+// ping retries until the database answers or cfg.Attempts are used up.
+func ping(db *bun.DB, cfg config.PingConfig) error {
+	backoff := cfg.Backoff
+	for attempt := 1; ; attempt++ {
+		ctx, cancel := context.Background(), func() {}
+		if cfg.Timeout > 0 {
+			ctx, cancel = context.WithTimeout(ctx, cfg.Timeout)
+		}
+		err := db.PingContext(ctx)
+		cancel()
+		if err == nil || attempt >= cfg.Attempts {
+			return err
+		}
 
-	db := bun.NewDB(_db, mysqldialect.New())
-	return db
+		slog.Warn("Database unreachable, retrying",
+			"attempt", attempt, "attempts", cfg.Attempts, "backoff", backoff, "error", err)
+		time.Sleep(backoff)
+		backoff *= 2
+		if cfg.MaxBackoff > 0 && backoff > cfg.MaxBackoff {
+			backoff = cfg.MaxBackoff
+		}
+	}
 }
@@ -0,0 +1,174 @@
+package bun
+
+import (
+	"context"
+	"embed"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/Jiei-S/boilerplate-clean-architecture/internal/infrastructure/config"
+	"github.com/uptrace/bun"
+	"github.com/uptrace/bun/dialect"
+	"github.com/uptrace/bun/migrate"
+)
+
+//go:embed migrations
+var migrationsFS embed.FS
+
+// migrationDirs maps each dialect to its directory under migrations.
+var migrationDirs = map[dialect.Name]string{
+	dialect.MySQL:  config.DriverMySQL,
+	dialect.PG:     config.DriverPostgres,
+	dialect.SQLite: config.DriverSQLite,
+}
+
+// Waiting for the migration lock polls it every migrationLockBackoff,
+// doubled after each attempt up to migrationLockMaxBackoff.
+const (
+	migrationLockBackoff    = 100 * time.Millisecond
+	migrationLockMaxBackoff = 5 * time.Second
+)
+
+var (
+	migrationFileRE = regexp.MustCompile(`^(\d+)_[0-9a-z_\-]+\.(up|down)\.sql$`)
+	migrationNameRE = regexp.MustCompile(`^[0-9a-z_\-]+$`)
+)
+
+// NewMigrator returns a migrator for the embedded migrations of the dialect
+// of db. A migration is recorded as applied only once it succeeded, so that
+// a failed one runs again on the next attempt.
+func NewMigrator(db *bun.DB) (*migrate.Migrator, error) {
+	dir, ok := migrationDirs[db.Dialect().Name()]
+	if !ok {
+		return nil, fmt.Errorf("no migrations for dialect %s", db.Dialect().Name())
+	}
+	fsys, err := fs.Sub(migrationsFS, path.Join("migrations", dir))
+	if err != nil {
+		return nil, err
+	}
+
+	migrations := migrate.NewMigrations()
+	if err := migrations.Discover(fsys); err != nil {
+		return nil, err
+	}
+	return migrate.NewMigrator(db, migrations, migrate.WithMarkAppliedOnSuccess(true)), nil
+}
+
+// Migrate applies every pending migration as one group. It waits up to
+// lockTimeout for another instance that is migrating.
+func Migrate(ctx context.Context, migrator *migrate.Migrator, lockTimeout time.Duration) (*migrate.MigrationGroup, error) {
+	var group *migrate.MigrationGroup
+	err := withMigrationLock(ctx, migrator, lockTimeout, func() (err error) {
+		group, err = migrator.Migrate(ctx)
+		return err
+	})
+	return group, err
+}
+
+// Rollback reverts the last group of applied migrations. It waits up to
+// lockTimeout for another instance that is migrating.
+func Rollback(ctx context.Context, migrator *migrate.Migrator, lockTimeout time.Duration) (*migrate.MigrationGroup, error) {
+	var group *migrate.MigrationGroup
+	err := withMigrationLock(ctx, migrator, lockTimeout, func() (err error) {
+		group, err = migrator.Rollback(ctx)
+		return err
+	})
+	return group, err
+}
+
+// withMigrationLock runs fn while holding the row in the migration lock
+// table, so that instances starting together do not migrate concurrently:
+// the others wait up to lockTimeout for it, and then find nothing left to
+// migrate. A lock left behind by a crashed run is released with Unlock.
+func withMigrationLock(ctx context.Context, migrator *migrate.Migrator, lockTimeout time.Duration, fn func() error) error {
+	if err := migrator.Init(ctx); err != nil {
+		return err
+	}
+	if err := lockMigrations(ctx, migrator, lockTimeout); err != nil {
+		return err
+	}
+
+	err := fn()
+	if unlockErr := migrator.Unlock(ctx); unlockErr != nil && err == nil {
+		err = unlockErr
+	}
+	return err
+}
+
+// lockMigrations takes the migration lock, retrying while another instance
+// holds it until lockTimeout has passed.
+func lockMigrations(ctx context.Context, migrator *migrate.Migrator, lockTimeout time.Duration) error {
+	deadline := time.Now().Add(lockTimeout)
+	backoff := migrationLockBackoff
+	for {
+		err := migrator.Lock(ctx)
+		if err == nil {
+			return nil
+		}
+		wait := time.Until(deadline)
+		if wait <= 0 {
+			return fmt.Errorf("%w; gave up after %s, run `migrate unlock` if no other instance is migrating", err, lockTimeout)
+		}
+		wait = min(wait, backoff)
+
+		slog.Info("Migrations locked, waiting", "backoff", wait, "error", err)
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(wait):
+		}
+		backoff *= 2
+		if backoff > migrationLockMaxBackoff {
+			backoff = migrationLockMaxBackoff
+		}
+	}
+}
+
+// CreateMigration writes empty up and down migrations named name into the
+// directory of every dialect under dir, numbered after the latest existing
+// migration. It returns the paths of the files written.
+func CreateMigration(dir, name string) ([]string, error) {
+	if !migrationNameRE.MatchString(name) {
+		return nil, errors.New("migration name must consist of lowercase letters, digits, '_' and '-'")
+	}
+
+	latest := 0
+	err := filepath.WalkDir(dir, func(_ string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		matches := migrationFileRE.FindStringSubmatch(d.Name())
+		if matches == nil {
+			return nil
+		}
+		if version, err := strconv.Atoi(matches[1]); err == nil && version > latest {
+			latest = version
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var files []string
+	for _, dialectDir := range migrationDirs {
+		for _, direction := range []string{"up", "down"} {
+			file := filepath.Join(dir, dialectDir, fmt.Sprintf("%06d_%s.%s.sql", latest+1, name, direction))
+			if err := os.WriteFile(file, nil, 0o644); err != nil {
+				return files, err
+			}
+			files = append(files, file)
+		}
+	}
+	sort.Strings(files)
+	return files, nil
+}
@@ -0,0 +1,12 @@
+CREATE TABLE IF NOT EXISTS idempotency_keys(
+    id VARCHAR (36) PRIMARY KEY NOT NULL,
+    idempotency_key VARCHAR (255) NOT NULL,
+    subject VARCHAR (255) NOT NULL,
+    fingerprint CHAR (64) NOT NULL,
+    response MEDIUMTEXT NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    expires_at TIMESTAMP NOT NULL,
+    CONSTRAINT uq_idempotency_keys_subject_key UNIQUE (subject, idempotency_key)
+);
+--bun:split
+CREATE INDEX idx_idempotency_keys_expires_at ON idempotency_keys (expires_at);
@@ -0,0 +1 @@
+ALTER TABLE users ADD CONSTRAINT uq_users_name UNIQUE (first_name, last_name);
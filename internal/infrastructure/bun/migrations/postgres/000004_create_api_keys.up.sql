@@ -0,0 +1,12 @@
+CREATE TABLE IF NOT EXISTS api_keys(
+    id VARCHAR (36) PRIMARY KEY NOT NULL,
+    name VARCHAR (100) NOT NULL,
+    prefix VARCHAR (16) NOT NULL,
+    key_hash CHAR (64) NOT NULL,
+    scopes VARCHAR (1000) NOT NULL,
+    created_by VARCHAR (255) NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    last_used_at TIMESTAMPTZ NULL,
+    revoked_at TIMESTAMPTZ NULL,
+    CONSTRAINT uq_api_keys_key_hash UNIQUE (key_hash)
+);
@@ -0,0 +1 @@
+CREATE UNIQUE INDEX uq_users_name ON users (first_name, last_name);
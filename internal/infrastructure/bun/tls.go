@@ -0,0 +1,48 @@
+package bun
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/Jiei-S/boilerplate-clean-architecture/internal/infrastructure/config"
+)
+
+// newTLSConfig returns the TLS configuration of cfg.Mode, or nil when TLS is
+// disabled.
+func newTLSConfig(cfg config.TLSConfig) (*tls.Config, error) {
+	switch cfg.Mode {
+	case "", config.TLSModeDisable:
+		return nil, nil
+	}
+
+	c := &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		ServerName: cfg.ServerName,
+		// require encrypts without authenticating the server, like
+		// sslmode=require of libpq.
+		InsecureSkipVerify: cfg.Mode == config.TLSModeRequire,
+	}
+
+	if cfg.CAFile != "" {
+		pem, err := os.ReadFile(cfg.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("read TLS CA file: %w", err)
+		}
+		c.RootCAs = x509.NewCertPool()
+		if !c.RootCAs.AppendCertsFromPEM(pem) {
+			return nil, errors.New("TLS CA file has no PEM certificates")
+		}
+	}
+
+	if cfg.CertFile != "" {
+		cert, err := tls.LoadX509KeyPair(cfg.CertFile, cfg.KeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("load TLS client certificate: %w", err)
+		}
+		c.Certificates = []tls.Certificate{cert}
+	}
+	return c, nil
+}
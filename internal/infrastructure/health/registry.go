@@ -0,0 +1,106 @@
+package health
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/Jiei-S/boilerplate-clean-architecture/internal/infrastructure/config"
+)
+
+// Checker probes a dependency and returns why it is unusable, if it is.
+type Checker func(ctx context.Context) error
+
+// Result is the outcome of the last check of a component.
+type Result struct {
+	Name      string
+	Err       error
+	Latency   time.Duration
+	CheckedAt time.Time
+}
+
+func (r Result) Healthy() bool {
+	return r.Err == nil
+}
+
+// Registry runs the registered checkers in the order they were registered.
+// Each result is reused for the cache TTL, and probes arriving while a
+// check runs wait for it instead of starting another one.
+type Registry struct {
+	timeout  time.Duration
+	cacheTTL time.Duration
+	checks   []*check
+}
+
+type check struct {
+	name    string
+	checker Checker
+
+	mu     sync.Mutex
+	result *Result
+}
+
+// Register adds a checker for the component name.
+func (r *Registry) Register(name string, checker Checker) {
+	r.checks = append(r.checks, &check{
+		name:    name,
+		checker: checker,
+	})
+}
+
+// Check runs every checker concurrently and returns their results in the
+// order the checkers were registered.
+func (r *Registry) Check(ctx context.Context) []Result {
+	results := make([]Result, len(r.checks))
+	var wg sync.WaitGroup
+	for i, c := range r.checks {
+		wg.Add(1)
+		go func(i int, c *check) {
+			defer wg.Done()
+			results[i] = r.run(ctx, c)
+		}(i, c)
+	}
+	wg.Wait()
+	return results
+}
+
+func (r *Registry) run(ctx context.Context, c *check) Result {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.result != nil && time.Since(c.result.CheckedAt) < r.cacheTTL {
+		return *c.result
+	}
+
+	// The result is shared with other probes, so it must not depend on
+	// whether the client of this one went away.
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), r.timeout)
+	defer cancel()
+
+	start := time.Now()
+	done := make(chan error, 1)
+	go func() {
+		done <- c.checker(ctx)
+	}()
+	var err error
+	select {
+	case err = <-done:
+	case <-ctx.Done():
+		// Do not wait for a checker that ignores its context.
+		err = ctx.Err()
+	}
+	c.result = &Result{
+		Name:      c.name,
+		Err:       err,
+		Latency:   time.Since(start),
+		CheckedAt: start,
+	}
+	return *c.result
+}
+
+func NewRegistry(cfg config.HealthConfig) *Registry {
+	return &Registry{
+		timeout:  cfg.Timeout,
+		cacheTTL: cfg.CacheTTL,
+	}
+}
@@ -0,0 +1,116 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/Jiei-S/boilerplate-clean-architecture/internal/infrastructure/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRegistryCheck(t *testing.T) {
+	// counting returns a checker that counts its calls and fails with err.
+	counting := func(calls *atomic.Int32, err error) Checker {
+		return func(ctx context.Context) error {
+			calls.Add(1)
+			return err
+		}
+	}
+
+	t.Run("returns results in registration order", func(t *testing.T) {
+		r := NewRegistry(config.HealthConfig{Timeout: time.Second})
+		errDown := errors.New("down")
+		var calls atomic.Int32
+		r.Register("database", counting(&calls, nil))
+		r.Register("cache", counting(&calls, errDown))
+
+		results := r.Check(context.Background())
+		require.Len(t, results, 2)
+		assert.Equal(t, "database", results[0].Name)
+		assert.True(t, results[0].Healthy())
+		assert.Equal(t, "cache", results[1].Name)
+		assert.Equal(t, errDown, results[1].Err)
+	})
+
+	t.Run("times out a slow check", func(t *testing.T) {
+		r := NewRegistry(config.HealthConfig{Timeout: 20 * time.Millisecond})
+		release := make(chan struct{})
+		defer close(release)
+		// The checker ignores its context.
+		r.Register("database", func(ctx context.Context) error {
+			<-release
+			return nil
+		})
+
+		start := time.Now()
+		results := r.Check(context.Background())
+		assert.Less(t, time.Since(start), time.Second)
+		assert.ErrorIs(t, results[0].Err, context.DeadlineExceeded)
+		assert.False(t, results[0].Healthy())
+	})
+
+	t.Run("reuses a result within the TTL", func(t *testing.T) {
+		r := NewRegistry(config.HealthConfig{Timeout: time.Second, CacheTTL: time.Hour})
+		var calls atomic.Int32
+		r.Register("database", counting(&calls, nil))
+
+		first := r.Check(context.Background())
+		second := r.Check(context.Background())
+		assert.Equal(t, int32(1), calls.Load())
+		assert.Equal(t, first, second)
+	})
+
+	t.Run("checks again once the TTL has passed", func(t *testing.T) {
+		r := NewRegistry(config.HealthConfig{Timeout: time.Second, CacheTTL: time.Hour})
+		var calls atomic.Int32
+		r.Register("database", counting(&calls, nil))
+
+		r.Check(context.Background())
+		r.checks[0].result.CheckedAt = time.Now().Add(-time.Hour)
+		r.Check(context.Background())
+		assert.Equal(t, int32(2), calls.Load())
+	})
+
+	t.Run("shares a running check between probes", func(t *testing.T) {
+		r := NewRegistry(config.HealthConfig{Timeout: time.Second, CacheTTL: time.Hour})
+		var calls atomic.Int32
+		release := make(chan struct{})
+		r.Register("database", func(ctx context.Context) error {
+			calls.Add(1)
+			<-release
+			return nil
+		})
+
+		var wg sync.WaitGroup
+		for i := 0; i < 5; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				r.Check(context.Background())
+			}()
+		}
+		require.Eventually(t, func() bool { return calls.Load() == 1 }, time.Second, time.Millisecond)
+		close(release)
+		wg.Wait()
+		assert.Equal(t, int32(1), calls.Load())
+	})
+
+	t.Run("does not cancel the check with its caller", func(t *testing.T) {
+		r := NewRegistry(config.HealthConfig{Timeout: time.Second, CacheTTL: time.Hour})
+		r.Register("database", func(ctx context.Context) error {
+			return ctx.Err()
+		})
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		results := r.Check(ctx)
+		assert.True(t, results[0].Healthy())
+		// The result cached for other probes is healthy as well.
+		assert.True(t, r.Check(context.Background())[0].Healthy())
+	})
+}
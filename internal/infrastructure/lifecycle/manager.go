@@ -0,0 +1,149 @@
+package lifecycle
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Readiness reports whether the instance should receive traffic. It turns
+// ready once the server listens and unready as soon as shutdown begins.
+type Readiness struct {
+	ready atomic.Bool
+}
+
+func (r *Readiness) Ready() bool {
+	return r.ready.Load()
+}
+
+func NewReadiness() *Readiness {
+	return &Readiness{}
+}
+
+// Worker runs in the background until ctx is cancelled.
+type Worker func(ctx context.Context)
+
+type closer struct {
+	name string
+	fn   func()
+}
+
+// Manager runs the HTTP server and background workers, and shuts them down
+// in order: it flips readiness, waits drainDelay for load balancers to
+// notice, drains the HTTP server, stops the workers and finally runs the
+// closers, e.g. of the database pool. shutdownTimeout bounds draining and
+// stopping the workers together.
+type Manager struct {
+	drainDelay      time.Duration
+	shutdownTimeout time.Duration
+
+	server    *http.Server
+	readiness *Readiness
+	workers   map[string]Worker
+	closers   []closer
+	// sleep waits out drainDelay; tests replace it.
+	sleep func(d time.Duration)
+}
+
+// AddWorker runs w while the server runs.
+func (m *Manager) AddWorker(name string, w Worker) {
+	m.workers[name] = w
+}
+
+// AddCloser runs fn after the server and the workers stopped. Closers run in
+// the reverse order of their addition.
+func (m *Manager) AddCloser(name string, fn func()) {
+	m.closers = append(m.closers, closer{name: name, fn: fn})
+}
+
+// Run serves until ctx is done or the server fails, then shuts down. It
+// returns nil only if everything stopped cleanly.
+func (m *Manager) Run(ctx context.Context) error {
+	ln, err := net.Listen("tcp", m.server.Addr)
+	if err != nil {
+		m.close()
+		return fmt.Errorf("server: %w", err)
+	}
+
+	workerCtx, stopWorkers := context.WithCancel(context.Background())
+	defer stopWorkers()
+	var workers sync.WaitGroup
+	for name, w := range m.workers {
+		workers.Add(1)
+		go func(name string, w Worker) {
+			defer workers.Done()
+			w(workerCtx)
+			slog.Info("Worker stopped", "worker", name)
+		}(name, w)
+	}
+
+	serveErr := make(chan error, 1)
+	go func() {
+		serveErr <- m.server.Serve(ln)
+	}()
+	m.readiness.ready.Store(true)
+	slog.Info("Server listening", "addr", m.server.Addr)
+
+	var errs []error
+	select {
+	case <-ctx.Done():
+		slog.Info("Shutting down server")
+		m.readiness.ready.Store(false)
+		m.sleep(m.drainDelay)
+	case err := <-serveErr:
+		m.readiness.ready.Store(false)
+		errs = append(errs, fmt.Errorf("server: %w", err))
+	}
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), m.shutdownTimeout)
+	defer cancel()
+
+	if err := m.server.Shutdown(shutdownCtx); err != nil {
+		errs = append(errs, fmt.Errorf("drain server: %w", err))
+		m.server.Close()
+	}
+
+	stopWorkers()
+	stopped := make(chan struct{})
+	go func() {
+		workers.Wait()
+		close(stopped)
+	}()
+	select {
+	case <-stopped:
+	case <-shutdownCtx.Done():
+		errs = append(errs, errors.New("stop workers: shutdown timeout exceeded"))
+	}
+
+	m.close()
+	return errors.Join(errs...)
+}
+
+func (m *Manager) close() {
+	for i := len(m.closers) - 1; i >= 0; i-- {
+		slog.Info("Closing", "resource", m.closers[i].name)
+		m.closers[i].fn()
+	}
+}
+
+func NewManager(
+	server *http.Server,
+	readiness *Readiness,
+	drainDelay time.Duration,
+	shutdownTimeout time.Duration,
+) *Manager {
+	return &Manager{
+		drainDelay:      drainDelay,
+		shutdownTimeout: shutdownTimeout,
+		server:          server,
+		readiness:       readiness,
+		workers:         map[string]Worker{},
+		sleep:           time.Sleep,
+	}
+}
@@ -0,0 +1,154 @@
+package lifecycle
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// recorder keeps the steps of a shutdown in the order they happen.
+type recorder struct {
+	mu    sync.Mutex
+	steps []string
+}
+
+func (r *recorder) record(format string, args ...interface{}) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.steps = append(r.steps, fmt.Sprintf(format, args...))
+}
+
+func (r *recorder) get() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]string(nil), r.steps...)
+}
+
+// freeAddr returns a local address that nothing listens on.
+func freeAddr(t *testing.T) string {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	addr := ln.Addr().String()
+	require.NoError(t, ln.Close())
+	return addr
+}
+
+// run starts m, waits until it is ready and returns the function that stops
+// it and returns the result of Run.
+func run(t *testing.T, m *Manager) func() error {
+	t.Helper()
+	ctx, cancel := context.WithCancel(context.Background())
+	result := make(chan error, 1)
+	go func() {
+		result <- m.Run(ctx)
+	}()
+	require.Eventually(t, m.readiness.Ready, time.Second, time.Millisecond)
+	return func() error {
+		cancel()
+		select {
+		case err := <-result:
+			return err
+		case <-time.After(5 * time.Second):
+			t.Fatal("Run did not return")
+			return nil
+		}
+	}
+}
+
+func TestManagerShutdownOrder(t *testing.T) {
+	rec := &recorder{}
+	started, release := make(chan struct{}), make(chan struct{})
+	server := &http.Server{
+		Addr: freeAddr(t),
+		Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			close(started)
+			<-release
+			rec.record("request drained")
+		}),
+	}
+	// Shutdown runs its hooks before waiting for the requests in flight.
+	server.RegisterOnShutdown(func() {
+		close(release)
+	})
+
+	readiness := NewReadiness()
+	m := NewManager(server, readiness, 5*time.Second, time.Second)
+	m.sleep = func(d time.Duration) {
+		rec.record("drain %s, ready %t", d, readiness.Ready())
+	}
+	m.AddWorker("sweeper", func(ctx context.Context) {
+		<-ctx.Done()
+		rec.record("worker stopped")
+	})
+	m.AddCloser("database", func() { rec.record("close database") })
+	m.AddCloser("tracing", func() { rec.record("close tracing") })
+
+	stop := run(t, m)
+	go http.Get("http://" + server.Addr)
+	<-started
+
+	require.NoError(t, stop())
+	assert.Equal(t, []string{
+		"drain 5s, ready false",
+		"request drained",
+		"worker stopped",
+		"close tracing",
+		"close database",
+	}, rec.get())
+}
+
+func TestManagerShutdownTimeout(t *testing.T) {
+	rec := &recorder{}
+	done := make(chan struct{})
+	defer close(done)
+	started := make(chan struct{})
+	server := &http.Server{
+		Addr: freeAddr(t),
+		Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			close(started)
+			<-done
+		}),
+	}
+
+	m := NewManager(server, NewReadiness(), 0, 50*time.Millisecond)
+	m.AddWorker("stuck", func(ctx context.Context) {
+		<-done
+	})
+	m.AddCloser("database", func() { rec.record("close database") })
+
+	stop := run(t, m)
+	go http.Get("http://" + server.Addr)
+	<-started
+
+	err := stop()
+	require.Error(t, err)
+	// Both problems are reported, and the closers run anyway.
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Contains(t, err.Error(), "drain server: ")
+	assert.Contains(t, err.Error(), "stop workers: shutdown timeout exceeded")
+	assert.Equal(t, []string{"close database"}, rec.get())
+}
+
+func TestManagerListenError(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer ln.Close()
+
+	rec := &recorder{}
+	m := NewManager(&http.Server{Addr: ln.Addr().String()}, NewReadiness(), 0, time.Second)
+	m.AddWorker("sweeper", func(ctx context.Context) { rec.record("worker started") })
+	m.AddCloser("database", func() { rec.record("close database") })
+
+	err = m.Run(context.Background())
+	assert.ErrorContains(t, err, "server: ")
+	assert.False(t, m.readiness.Ready())
+	assert.Equal(t, []string{"close database"}, rec.get())
+}
@@ -0,0 +1,63 @@
+package logging
+
+import (
+	"log/slog"
+	"net/url"
+	"regexp"
+)
+
+// redacted replaces personal data, as it does secrets in the configuration.
+const redacted = "xxxxx"
+
+// piiKeys are the attributes and query parameters that hold user names.
+var piiKeys = map[string]bool{
+	"firstName":  true,
+	"lastName":   true,
+	"first_name": true,
+	"last_name":  true,
+}
+
+// piiPatterns find user names in messages that quote them, such as the
+// errors MySQL and the detail PostgreSQL report for a duplicate name. The
+// error of SQLite names only the columns.
+var piiPatterns = []*regexp.Regexp{
+	regexp.MustCompile(`(Duplicate entry ').*(' for key)`),
+	regexp.MustCompile(`(Key \(.*?\)=\().*(\) already exists)`),
+}
+
+func redact(_ []string, a slog.Attr) slog.Attr {
+	if piiKeys[a.Key] {
+		return slog.String(a.Key, redacted)
+	}
+	switch a.Value.Kind() {
+	case slog.KindString:
+		return slog.String(a.Key, RedactString(a.Value.String()))
+	case slog.KindAny:
+		if err, ok := a.Value.Any().(error); ok {
+			return slog.String(a.Key, RedactString(err.Error()))
+		}
+	}
+	return a
+}
+
+// RedactString returns s with the user names it quotes masked.
+func RedactString(s string) string {
+	for _, p := range piiPatterns {
+		s = p.ReplaceAllString(s, "${1}"+redacted+"${2}")
+	}
+	return s
+}
+
+// RedactURL returns the path and query of u with user names masked.
+func RedactURL(u *url.URL) string {
+	query := u.Query()
+	if len(query) == 0 {
+		return u.Path
+	}
+	for key := range query {
+		if piiKeys[key] {
+			query.Set(key, redacted)
+		}
+	}
+	return u.Path + "?" + query.Encode()
+}
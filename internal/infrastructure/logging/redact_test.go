@@ -0,0 +1,91 @@
+package logging
+
+import (
+	"errors"
+	"log/slog"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRedactString(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{
+			name: "mysql duplicate entry",
+			in:   "Error 1062 (23000): Duplicate entry 'Taro-Yamada' for key 'users.users_name_unique'",
+			want: "Error 1062 (23000): Duplicate entry 'xxxxx' for key 'users.users_name_unique'",
+		},
+		{
+			name: "mysql duplicate entry quoting a quote",
+			in:   "Error 1062 (23000): Duplicate entry 'O'Brien-Pat' for key 'users.users_name_unique'",
+			want: "Error 1062 (23000): Duplicate entry 'xxxxx' for key 'users.users_name_unique'",
+		},
+		{
+			name: "postgres unique violation detail",
+			in:   `duplicate key value violates unique constraint "users_name_unique": Key (first_name, last_name)=(Taro, Yamada) already exists.`,
+			want: `duplicate key value violates unique constraint "users_name_unique": Key (first_name, last_name)=(xxxxx) already exists.`,
+		},
+		{
+			name: "sqlite unique violation names only the columns",
+			in:   "constraint failed: UNIQUE constraint failed: users.first_name, users.last_name (2067)",
+			want: "constraint failed: UNIQUE constraint failed: users.first_name, users.last_name (2067)",
+		},
+		{
+			name: "other messages",
+			in:   "resource not found",
+			want: "resource not found",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, RedactString(tt.in))
+		})
+	}
+}
+
+func TestRedact(t *testing.T) {
+	duplicate := "Duplicate entry 'Taro-Yamada' for key 'users.users_name_unique'"
+	tests := []struct {
+		name string
+		in   slog.Attr
+		want slog.Attr
+	}{
+		{"first name", slog.String("firstName", "Taro"), slog.String("firstName", redacted)},
+		{"last name column", slog.String("last_name", "Yamada"), slog.String("last_name", redacted)},
+		{"non-string name", slog.Int("firstName", 1), slog.String("firstName", redacted)},
+		{"message", slog.String("msg", duplicate), slog.String("msg", "Duplicate entry 'xxxxx' for key 'users.users_name_unique'")},
+		{"error", slog.Any("error", errors.New(duplicate)), slog.String("error", "Duplicate entry 'xxxxx' for key 'users.users_name_unique'")},
+		{"other attribute", slog.Int("status", 409), slog.Int("status", 409)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := redact(nil, tt.in)
+			assert.True(t, tt.want.Equal(got), "got %s", got)
+		})
+	}
+}
+
+func TestRedactURL(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{"without query", "/users", "/users"},
+		{"names", "/users?firstName=Taro&lastName=Yamada&limit=10", "/users?firstName=xxxxx&lastName=xxxxx&limit=10"},
+		{"other parameters", "/users?limit=10&offset=20", "/users?limit=10&offset=20"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			u, err := url.Parse(tt.in)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, RedactURL(u))
+		})
+	}
+}
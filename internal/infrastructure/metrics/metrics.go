@@ -0,0 +1,119 @@
+package metrics
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	pkgErr "github.com/Jiei-S/boilerplate-clean-architecture/pkg/error"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/uptrace/bun"
+)
+
+const namespace = "app"
+
+// Metrics collects the metrics of the application and serves them in the
+// Prometheus text format. Requests are labeled by the operationId of the
+// OpenAPI operation rather than by path, so that the number of series does
+// not grow with the IDs in the URLs.
+type Metrics struct {
+	registry *prometheus.Registry
+	handler  http.Handler
+
+	requests *prometheus.CounterVec
+	duration *prometheus.HistogramVec
+	errors   *prometheus.CounterVec
+	queries  *prometheus.HistogramVec
+}
+
+// ObserveRequest records a request that operation answered with status.
+func (m *Metrics) ObserveRequest(operation, method string, status int, duration time.Duration) {
+	m.requests.WithLabelValues(operation, method, strconv.Itoa(status)).Inc()
+	m.duration.WithLabelValues(operation, method).Observe(duration.Seconds())
+}
+
+// ObserveError records an error that a request failed with.
+func (m *Metrics) ObserveError(err *pkgErr.ApplicationError) {
+	m.errors.WithLabelValues(err.Code().String(), err.Level().String()).Inc()
+}
+
+// RegisterDB exposes the connection pool statistics of db.
+func (m *Metrics) RegisterDB(db *sql.DB, name string) error {
+	return m.registry.Register(collectors.NewDBStatsCollector(db, name))
+}
+
+// QueryHook returns a bun query hook that records the duration of each query
+// by operation, e.g. SELECT.
+func (m *Metrics) QueryHook() bun.QueryHook {
+	return queryHook{queries: m.queries}
+}
+
+func (m *Metrics) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	m.handler.ServeHTTP(w, r)
+}
+
+type queryHook struct {
+	queries *prometheus.HistogramVec
+}
+
+func (h queryHook) BeforeQuery(ctx context.Context, _ *bun.QueryEvent) context.Context {
+	return ctx
+}
+
+func (h queryHook) AfterQuery(_ context.Context, event *bun.QueryEvent) {
+	result := "success"
+	if event.Err != nil && !errors.Is(event.Err, sql.ErrNoRows) {
+		result = "error"
+	}
+	h.queries.WithLabelValues(event.Operation(), result).Observe(time.Since(event.StartTime).Seconds())
+}
+
+func New() (*Metrics, error) {
+	m := &Metrics{
+		registry: prometheus.NewRegistry(),
+		requests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "http_requests_total",
+			Help:      "Number of HTTP requests by operation, method and status code.",
+		}, []string{"operation", "method", "status"}),
+		duration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "http_request_duration_seconds",
+			Help:      "Duration of HTTP requests by operation and method.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"operation", "method"}),
+		errors: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "application_errors_total",
+			Help:      "Number of requests that failed with an application error, by code and level.",
+		}, []string{"code", "level"}),
+		queries: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "db_query_duration_seconds",
+			Help:      "Duration of database queries by operation and result.",
+			Buckets:   []float64{.0005, .001, .0025, .005, .01, .025, .05, .1, .25, .5, 1, 2.5},
+		}, []string{"operation", "result"}),
+	}
+
+	for _, c := range []prometheus.Collector{
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		m.requests,
+		m.duration,
+		m.errors,
+		m.queries,
+	} {
+		if err := m.registry.Register(c); err != nil {
+			return nil, err
+		}
+	}
+	m.handler = promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{
+		Registry: m.registry,
+	})
+	return m, nil
+}
@@ -3,15 +3,96 @@
 // Code generated by github.com/deepmap/oapi-codegen version v1.13.4 DO NOT EDIT.
 package openapi
 
+import (
+	"time"
+)
+
+const (
+	ApiKeyAuthScopes = "apiKeyAuth.Scopes"
+	BearerAuthScopes = "bearerAuth.Scopes"
+)
+
 // Defines values for HealthStatus.
 const (
 	Healthy   HealthStatus = "healthy"
 	Unhealthy HealthStatus = "unhealthy"
 )
 
-// Error defines model for Error.
-type Error struct {
-	Code    int32  `json:"code"`
+// Defines values for ListUsersParamsSort.
+const (
+	Age            ListUsersParamsSort = "age"
+	CreatedAt      ListUsersParamsSort = "createdAt"
+	FirstName      ListUsersParamsSort = "firstName"
+	LastName       ListUsersParamsSort = "lastName"
+	MinusAge       ListUsersParamsSort = "-age"
+	MinusCreatedAt ListUsersParamsSort = "-createdAt"
+	MinusFirstName ListUsersParamsSort = "-firstName"
+	MinusLastName  ListUsersParamsSort = "-lastName"
+)
+
+// ApiKey defines model for ApiKey.
+type ApiKey struct {
+	CreatedAt time.Time `json:"createdAt"`
+
+	// CreatedBy subject of the caller that created the key
+	CreatedBy string `json:"createdBy"`
+	Id        string `json:"id"`
+
+	// LastUsedAt last authentication with the key, to the minute
+	LastUsedAt *time.Time `json:"lastUsedAt,omitempty"`
+	Name       string     `json:"name"`
+
+	// Prefix beginning of the key, to tell keys apart
+	Prefix    string     `json:"prefix"`
+	RevokedAt *time.Time `json:"revokedAt,omitempty"`
+	Scopes    []string   `json:"scopes"`
+}
+
+// ApiKeyList defines model for ApiKeyList.
+type ApiKeyList struct {
+	Items []ApiKey `json:"items"`
+}
+
+// ComponentHealth defines model for ComponentHealth.
+type ComponentHealth struct {
+	// CheckedAt time of the last check, which may be cached
+	CheckedAt time.Time `json:"checkedAt"`
+
+	// Error why the component is unhealthy; the cause of a failed check is only logged
+	Error *string `json:"error,omitempty"`
+
+	// LatencyMs duration of the last check in milliseconds
+	LatencyMs float64 `json:"latencyMs"`
+
+	// Name name of the component, e.g. database
+	Name   string       `json:"name"`
+	Status HealthStatus `json:"status"`
+}
+
+// CreatedApiKey defines model for CreatedApiKey.
+type CreatedApiKey struct {
+	CreatedAt time.Time `json:"createdAt"`
+
+	// CreatedBy subject of the caller that created the key
+	CreatedBy string `json:"createdBy"`
+	Id        string `json:"id"`
+
+	// Key the key in the clear, to send in the X-API-Key header
+	Key string `json:"key"`
+
+	// LastUsedAt last authentication with the key, to the minute
+	LastUsedAt *time.Time `json:"lastUsedAt,omitempty"`
+	Name       string     `json:"name"`
+
+	// Prefix beginning of the key, to tell keys apart
+	Prefix    string     `json:"prefix"`
+	RevokedAt *time.Time `json:"revokedAt,omitempty"`
+	Scopes    []string   `json:"scopes"`
+}
+
+// FieldError defines model for FieldError.
+type FieldError struct {
+	Field   string `json:"field"`
 	Message string `json:"message"`
 }
 
@@ -20,9 +101,45 @@ type Health struct {
 	Status HealthStatus `json:"status"`
 }
 
-// HealthStatus defines model for Health.Status.
+// HealthStatus defines model for HealthStatus.
 type HealthStatus string
 
+// Problem Problem details as defined by RFC 9457
+type Problem struct {
+	// Detail explanation specific to this occurrence of the problem
+	Detail *string `json:"detail,omitempty"`
+
+	// Details structured data about the error, e.g. the ID of the missing resource
+	Details *map[string]interface{} `json:"details,omitempty"`
+
+	// Fields invalid fields of the request, if any
+	Fields *[]FieldError `json:"fields,omitempty"`
+
+	// Instance URI reference that identifies this occurrence of the problem
+	Instance *string `json:"instance,omitempty"`
+
+	// Reason stable machine-readable error code, e.g. USER_NOT_FOUND
+	Reason string `json:"reason"`
+
+	// RequestId ID of the request, also sent in the X-Request-ID header, to quote when reporting the problem
+	RequestId *string `json:"requestId,omitempty"`
+
+	// Status HTTP status code
+	Status int32 `json:"status"`
+
+	// Title short summary of the problem type
+	Title string `json:"title"`
+
+	// Type URI reference that identifies the problem type
+	Type string `json:"type"`
+}
+
+// Readiness defines model for Readiness.
+type Readiness struct {
+	Components []ComponentHealth `json:"components"`
+	Status     HealthStatus      `json:"status"`
+}
+
 // User defines model for User.
 type User struct {
 	Age       int    `json:"age"`
@@ -31,20 +148,129 @@ type User struct {
 	LastName  string `json:"lastName"`
 }
 
-// Conflict defines model for Conflict.
-type Conflict = Error
+// UserList defines model for UserList.
+type UserList struct {
+	Items []User `json:"items"`
 
-// NotFound defines model for NotFound.
-type NotFound = Error
+	// NextCursor cursor of the next page, absent on the last page
+	NextCursor *string `json:"nextCursor,omitempty"`
+}
+
+// BadRequest Problem details as defined by RFC 9457
+type BadRequest = Problem
+
+// Conflict Problem details as defined by RFC 9457
+type Conflict = Problem
+
+// Forbidden Problem details as defined by RFC 9457
+type Forbidden = Problem
+
+// NotFound Problem details as defined by RFC 9457
+type NotFound = Problem
 
-// Unauthorized defines model for Unauthorized.
-type Unauthorized = Error
+// TooManyRequests Problem details as defined by RFC 9457
+type TooManyRequests = Problem
+
+// Unauthorized Problem details as defined by RFC 9457
+type Unauthorized = Problem
+
+// UnexpectedError Problem details as defined by RFC 9457
+type UnexpectedError = Problem
+
+// UnprocessableEntity Problem details as defined by RFC 9457
+type UnprocessableEntity = Problem
 
 // AddUser defines model for AddUser.
 type AddUser = interface{}
 
+// CreateApiKey defines model for CreateApiKey.
+type CreateApiKey struct {
+	Name string `json:"name"`
+
+	// Scopes actions that the key may perform, e.g. users:create
+	Scopes []string `json:"scopes"`
+}
+
+// PatchUser defines model for PatchUser.
+type PatchUser struct {
+	Age       *int32  `json:"age,omitempty"`
+	FirstName *string `json:"firstName,omitempty"`
+	LastName  *string `json:"lastName,omitempty"`
+}
+
+// UpdateUser defines model for UpdateUser.
+type UpdateUser struct {
+	Age       int32  `json:"age"`
+	FirstName string `json:"firstName"`
+	LastName  string `json:"lastName"`
+}
+
+// CreateApiKeyJSONBody defines parameters for CreateApiKey.
+type CreateApiKeyJSONBody struct {
+	Name string `json:"name"`
+
+	// Scopes actions that the key may perform, e.g. users:create
+	Scopes []string `json:"scopes"`
+}
+
+// ListUsersParams defines parameters for ListUsers.
+type ListUsersParams struct {
+	// FirstName first name to filter by
+	FirstName *string `form:"firstName,omitempty" json:"firstName,omitempty"`
+
+	// LastName last name to filter by
+	LastName *string `form:"lastName,omitempty" json:"lastName,omitempty"`
+
+	// MinAge minimum age to filter by (inclusive)
+	MinAge *int32 `form:"minAge,omitempty" json:"minAge,omitempty"`
+
+	// MaxAge maximum age to filter by (inclusive)
+	MaxAge *int32 `form:"maxAge,omitempty" json:"maxAge,omitempty"`
+
+	// Sort field to sort by, prefixed with "-" for descending order
+	Sort *ListUsersParamsSort `form:"sort,omitempty" json:"sort,omitempty"`
+
+	// Limit maximum number of users to return
+	Limit *int `form:"limit,omitempty" json:"limit,omitempty"`
+
+	// Cursor nextCursor of the previous page
+	Cursor *string `form:"cursor,omitempty" json:"cursor,omitempty"`
+}
+
+// ListUsersParamsSort defines parameters for ListUsers.
+type ListUsersParamsSort string
+
 // AddUserJSONBody defines parameters for AddUser.
 type AddUserJSONBody = interface{}
 
+// AddUserParams defines parameters for AddUser.
+type AddUserParams struct {
+	// IdempotencyKey Makes retries safe. The response to the first request with the key is replayed to later requests of the same caller with the key, for a day by default; a request with the key and a different body is rejected.
+	IdempotencyKey *string `json:"Idempotency-Key,omitempty"`
+}
+
+// PatchUserJSONBody defines parameters for PatchUser.
+type PatchUserJSONBody struct {
+	Age       *int32  `json:"age,omitempty"`
+	FirstName *string `json:"firstName,omitempty"`
+	LastName  *string `json:"lastName,omitempty"`
+}
+
+// UpdateUserJSONBody defines parameters for UpdateUser.
+type UpdateUserJSONBody struct {
+	Age       int32  `json:"age"`
+	FirstName string `json:"firstName"`
+	LastName  string `json:"lastName"`
+}
+
+// CreateApiKeyJSONRequestBody defines body for CreateApiKey for application/json ContentType.
+type CreateApiKeyJSONRequestBody CreateApiKeyJSONBody
+
 // AddUserJSONRequestBody defines body for AddUser for application/json ContentType.
 type AddUserJSONRequestBody = AddUserJSONBody
+
+// PatchUserJSONRequestBody defines body for PatchUser for application/json ContentType.
+type PatchUserJSONRequestBody PatchUserJSONBody
+
+// UpdateUserJSONRequestBody defines body for UpdateUser for application/json ContentType.
+type UpdateUserJSONRequestBody UpdateUserJSONBody
@@ -6,6 +6,7 @@ package openapi
 import (
 	"bytes"
 	"compress/gzip"
+	"context"
 	"encoding/base64"
 	"fmt"
 	"net/http"
@@ -21,27 +22,97 @@ import (
 // ServerInterface represents all server handlers.
 type ServerInterface interface {
 
+	// (GET /api-keys)
+	ListApiKeys(w http.ResponseWriter, r *http.Request)
+
+	// (POST /api-keys)
+	CreateApiKey(w http.ResponseWriter, r *http.Request)
+
+	// (DELETE /api-keys/{id})
+	RevokeApiKey(w http.ResponseWriter, r *http.Request, id string)
+
 	// (GET /health)
 	Health(w http.ResponseWriter, r *http.Request)
 
+	// (GET /livez)
+	Livez(w http.ResponseWriter, r *http.Request)
+
+	// (GET /metrics)
+	Metrics(w http.ResponseWriter, r *http.Request)
+
+	// (GET /readyz)
+	Readyz(w http.ResponseWriter, r *http.Request)
+
+	// (GET /users)
+	ListUsers(w http.ResponseWriter, r *http.Request, params ListUsersParams)
+
 	// (POST /users)
-	AddUser(w http.ResponseWriter, r *http.Request)
+	AddUser(w http.ResponseWriter, r *http.Request, params AddUserParams)
+
+	// (DELETE /users/{id})
+	DeleteUser(w http.ResponseWriter, r *http.Request, id string)
 
 	// (GET /users/{id})
 	FindUser(w http.ResponseWriter, r *http.Request, id string)
+
+	// (PATCH /users/{id})
+	PatchUser(w http.ResponseWriter, r *http.Request, id string)
+
+	// (PUT /users/{id})
+	UpdateUser(w http.ResponseWriter, r *http.Request, id string)
 }
 
 // Unimplemented server implementation that returns http.StatusNotImplemented for each endpoint.
 
 type Unimplemented struct{}
 
+// (GET /api-keys)
+func (_ Unimplemented) ListApiKeys(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// (POST /api-keys)
+func (_ Unimplemented) CreateApiKey(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// (DELETE /api-keys/{id})
+func (_ Unimplemented) RevokeApiKey(w http.ResponseWriter, r *http.Request, id string) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
 // (GET /health)
 func (_ Unimplemented) Health(w http.ResponseWriter, r *http.Request) {
 	w.WriteHeader(http.StatusNotImplemented)
 }
 
+// (GET /livez)
+func (_ Unimplemented) Livez(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// (GET /metrics)
+func (_ Unimplemented) Metrics(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// (GET /readyz)
+func (_ Unimplemented) Readyz(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// (GET /users)
+func (_ Unimplemented) ListUsers(w http.ResponseWriter, r *http.Request, params ListUsersParams) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
 // (POST /users)
-func (_ Unimplemented) AddUser(w http.ResponseWriter, r *http.Request) {
+func (_ Unimplemented) AddUser(w http.ResponseWriter, r *http.Request, params AddUserParams) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// (DELETE /users/{id})
+func (_ Unimplemented) DeleteUser(w http.ResponseWriter, r *http.Request, id string) {
 	w.WriteHeader(http.StatusNotImplemented)
 }
 
@@ -50,6 +121,16 @@ func (_ Unimplemented) FindUser(w http.ResponseWriter, r *http.Request, id strin
 	w.WriteHeader(http.StatusNotImplemented)
 }
 
+// (PATCH /users/{id})
+func (_ Unimplemented) PatchUser(w http.ResponseWriter, r *http.Request, id string) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// (PUT /users/{id})
+func (_ Unimplemented) UpdateUser(w http.ResponseWriter, r *http.Request, id string) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
 // ServerInterfaceWrapper converts contexts to parameters.
 type ServerInterfaceWrapper struct {
 	Handler            ServerInterface
@@ -59,6 +140,74 @@ type ServerInterfaceWrapper struct {
 
 type MiddlewareFunc func(http.Handler) http.Handler
 
+// ListApiKeys operation middleware
+func (siw *ServerInterfaceWrapper) ListApiKeys(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{})
+
+	ctx = context.WithValue(ctx, ApiKeyAuthScopes, []string{})
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.ListApiKeys(w, r)
+	}))
+
+	for i := len(siw.HandlerMiddlewares) - 1; i >= 0; i-- {
+		handler = siw.HandlerMiddlewares[i](handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// CreateApiKey operation middleware
+func (siw *ServerInterfaceWrapper) CreateApiKey(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{})
+
+	ctx = context.WithValue(ctx, ApiKeyAuthScopes, []string{})
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.CreateApiKey(w, r)
+	}))
+
+	for i := len(siw.HandlerMiddlewares) - 1; i >= 0; i-- {
+		handler = siw.HandlerMiddlewares[i](handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// RevokeApiKey operation middleware
+func (siw *ServerInterfaceWrapper) RevokeApiKey(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	// ------------- Path parameter "id" -------------
+	var id string
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "id", runtime.ParamLocationPath, chi.URLParam(r, "id"), &id)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "id", Err: err})
+		return
+	}
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{})
+
+	ctx = context.WithValue(ctx, ApiKeyAuthScopes, []string{})
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.RevokeApiKey(w, r, id)
+	}))
+
+	for i := len(siw.HandlerMiddlewares) - 1; i >= 0; i-- {
+		handler = siw.HandlerMiddlewares[i](handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
 // Health operation middleware
 func (siw *ServerInterfaceWrapper) Health(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
@@ -74,12 +223,197 @@ func (siw *ServerInterfaceWrapper) Health(w http.ResponseWriter, r *http.Request
 	handler.ServeHTTP(w, r.WithContext(ctx))
 }
 
+// Livez operation middleware
+func (siw *ServerInterfaceWrapper) Livez(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.Livez(w, r)
+	}))
+
+	for i := len(siw.HandlerMiddlewares) - 1; i >= 0; i-- {
+		handler = siw.HandlerMiddlewares[i](handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// Metrics operation middleware
+func (siw *ServerInterfaceWrapper) Metrics(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.Metrics(w, r)
+	}))
+
+	for i := len(siw.HandlerMiddlewares) - 1; i >= 0; i-- {
+		handler = siw.HandlerMiddlewares[i](handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// Readyz operation middleware
+func (siw *ServerInterfaceWrapper) Readyz(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.Readyz(w, r)
+	}))
+
+	for i := len(siw.HandlerMiddlewares) - 1; i >= 0; i-- {
+		handler = siw.HandlerMiddlewares[i](handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// ListUsers operation middleware
+func (siw *ServerInterfaceWrapper) ListUsers(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{})
+
+	ctx = context.WithValue(ctx, ApiKeyAuthScopes, []string{})
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params ListUsersParams
+
+	// ------------- Optional query parameter "firstName" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "firstName", r.URL.Query(), &params.FirstName)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "firstName", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "lastName" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "lastName", r.URL.Query(), &params.LastName)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "lastName", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "minAge" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "minAge", r.URL.Query(), &params.MinAge)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "minAge", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "maxAge" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "maxAge", r.URL.Query(), &params.MaxAge)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "maxAge", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "sort" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "sort", r.URL.Query(), &params.Sort)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "sort", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "limit" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "limit", r.URL.Query(), &params.Limit)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "limit", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "cursor" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "cursor", r.URL.Query(), &params.Cursor)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "cursor", Err: err})
+		return
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.ListUsers(w, r, params)
+	}))
+
+	for i := len(siw.HandlerMiddlewares) - 1; i >= 0; i-- {
+		handler = siw.HandlerMiddlewares[i](handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
 // AddUser operation middleware
 func (siw *ServerInterfaceWrapper) AddUser(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 
+	var err error
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{})
+
+	ctx = context.WithValue(ctx, ApiKeyAuthScopes, []string{})
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params AddUserParams
+
+	headers := r.Header
+
+	// ------------- Optional header parameter "Idempotency-Key" -------------
+	if valueList, found := headers[http.CanonicalHeaderKey("Idempotency-Key")]; found {
+		var IdempotencyKey string
+		n := len(valueList)
+		if n != 1 {
+			siw.ErrorHandlerFunc(w, r, &TooManyValuesForParamError{ParamName: "Idempotency-Key", Count: n})
+			return
+		}
+
+		err = runtime.BindStyledParameterWithLocation("simple", false, "Idempotency-Key", runtime.ParamLocationHeader, valueList[0], &IdempotencyKey)
+		if err != nil {
+			siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "Idempotency-Key", Err: err})
+			return
+		}
+
+		params.IdempotencyKey = &IdempotencyKey
+
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.AddUser(w, r, params)
+	}))
+
+	for i := len(siw.HandlerMiddlewares) - 1; i >= 0; i-- {
+		handler = siw.HandlerMiddlewares[i](handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// DeleteUser operation middleware
+func (siw *ServerInterfaceWrapper) DeleteUser(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	// ------------- Path parameter "id" -------------
+	var id string
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "id", runtime.ParamLocationPath, chi.URLParam(r, "id"), &id)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "id", Err: err})
+		return
+	}
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{})
+
+	ctx = context.WithValue(ctx, ApiKeyAuthScopes, []string{})
+
 	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		siw.Handler.AddUser(w, r)
+		siw.Handler.DeleteUser(w, r, id)
 	}))
 
 	for i := len(siw.HandlerMiddlewares) - 1; i >= 0; i-- {
@@ -104,6 +438,10 @@ func (siw *ServerInterfaceWrapper) FindUser(w http.ResponseWriter, r *http.Reque
 		return
 	}
 
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{})
+
+	ctx = context.WithValue(ctx, ApiKeyAuthScopes, []string{})
+
 	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		siw.Handler.FindUser(w, r, id)
 	}))
@@ -115,6 +453,66 @@ func (siw *ServerInterfaceWrapper) FindUser(w http.ResponseWriter, r *http.Reque
 	handler.ServeHTTP(w, r.WithContext(ctx))
 }
 
+// PatchUser operation middleware
+func (siw *ServerInterfaceWrapper) PatchUser(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	// ------------- Path parameter "id" -------------
+	var id string
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "id", runtime.ParamLocationPath, chi.URLParam(r, "id"), &id)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "id", Err: err})
+		return
+	}
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{})
+
+	ctx = context.WithValue(ctx, ApiKeyAuthScopes, []string{})
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.PatchUser(w, r, id)
+	}))
+
+	for i := len(siw.HandlerMiddlewares) - 1; i >= 0; i-- {
+		handler = siw.HandlerMiddlewares[i](handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// UpdateUser operation middleware
+func (siw *ServerInterfaceWrapper) UpdateUser(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	// ------------- Path parameter "id" -------------
+	var id string
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "id", runtime.ParamLocationPath, chi.URLParam(r, "id"), &id)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "id", Err: err})
+		return
+	}
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{})
+
+	ctx = context.WithValue(ctx, ApiKeyAuthScopes, []string{})
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.UpdateUser(w, r, id)
+	}))
+
+	for i := len(siw.HandlerMiddlewares) - 1; i >= 0; i-- {
+		handler = siw.HandlerMiddlewares[i](handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
 type UnescapedCookieParamError struct {
 	ParamName string
 	Err       error
@@ -228,15 +626,45 @@ func HandlerWithOptions(si ServerInterface, options ChiServerOptions) http.Handl
 		ErrorHandlerFunc:   options.ErrorHandlerFunc,
 	}
 
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/api-keys", wrapper.ListApiKeys)
+	})
+	r.Group(func(r chi.Router) {
+		r.Post(options.BaseURL+"/api-keys", wrapper.CreateApiKey)
+	})
+	r.Group(func(r chi.Router) {
+		r.Delete(options.BaseURL+"/api-keys/{id}", wrapper.RevokeApiKey)
+	})
 	r.Group(func(r chi.Router) {
 		r.Get(options.BaseURL+"/health", wrapper.Health)
 	})
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/livez", wrapper.Livez)
+	})
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/metrics", wrapper.Metrics)
+	})
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/readyz", wrapper.Readyz)
+	})
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/users", wrapper.ListUsers)
+	})
 	r.Group(func(r chi.Router) {
 		r.Post(options.BaseURL+"/users", wrapper.AddUser)
 	})
+	r.Group(func(r chi.Router) {
+		r.Delete(options.BaseURL+"/users/{id}", wrapper.DeleteUser)
+	})
 	r.Group(func(r chi.Router) {
 		r.Get(options.BaseURL+"/users/{id}", wrapper.FindUser)
 	})
+	r.Group(func(r chi.Router) {
+		r.Patch(options.BaseURL+"/users/{id}", wrapper.PatchUser)
+	})
+	r.Group(func(r chi.Router) {
+		r.Put(options.BaseURL+"/users/{id}", wrapper.UpdateUser)
+	})
 
 	return r
 }
@@ -244,19 +672,56 @@ func HandlerWithOptions(si ServerInterface, options ChiServerOptions) http.Handl
 // Base64 encoded, gzipped, json marshaled Swagger object
 var swaggerSpec = []string{
 
-	"H4sIAAAAAAAC/8xWQU/jOhD+K9a8d4xIH3Dg5daFRVtphdBqOaEeTDxpjBLba0+AbpX/vpqkSZs2iC6g",
-	"ZU+1OvY3n2e+b5wVpLZ01qChAMkKPP6oMNAnqzQ2f0yVugnoeZlaQ2iIl9K5QqeStDXxfbCmO6o9Kkhu",
-	"IdM+0JUsESIoZL+UC4R5BCHNsZQNzgL5h5YOIQFtCBfooY62ADbhQF6bBUd7yP1gXdcRKAyp147pQQLM",
-	"X5AVUingqMfgrAnt/c6tyQqd0gEX3ND+12MGCfwTb4oXt9EQf/beehih8T1HERymOtOohMdgK5+ieJRB",
-	"ZLYyStxVJChH3/4nRdpRqyP4grKg/N1IruHaal1ZumQCH1oCY6ktA9/2xsiKcuv1T/wDrAbZOLw+wYDt",
-	"oWQFzluHntauSK1qxJdZX0pqpXtyDNGIkksMYSjzXqrRwDMN5mb/vAezd/e4q4IhnUCSqmaFpioZLG92",
-	"LiGCynTrDeIzDNYwY5m7GTDM+0r7avXbrh4S1QqiF2bMzg0YQZvMMvaw+1MRdOkKFNPrGU+JUhq5QFEF",
-	"9IEbqqnAbohMr2cQwQP60J7972hyNGHq1qGRTkMCJ81fEThJeVOjOO+btkDaz/8NqfImiHabaHsgbMaj",
-	"QAT0Dzrla3HZG9XPFCSdEHZm2fFkcoBbxjzSw2wmw65L1vy6ndBsyGRV0Jtz7hlwmLky+OQwJVQCOw/X",
-	"EcRti1iUNowU9tyjJBRSGHxs2rlXxe5ti7aeveXzdLdexrg7Wr+qBYcNrDbBSD1Yitt9OJ38/+Ye9O/g",
-	"M/lk4VGqpcAnHSj8Ld2PV1rVL1pLNu0XdzKgEtYIKYI2iwLF7GJPE5fadKJw0ssSqRHZ7S727II9Wq0/",
-	"LTKklO2oOcTWhwhMM8raWbUZXuQr3P4C2h1083fz9I6MDlDR6cvN6r8VPlgA/EqjfxjvzVebykK0cX4B",
-	"fcHzi8glcVxwLLeBkrPJ2QTqef0rAAD///qDeNoDCwAA",
+	"H4sIAAAAAAAC/+xbbW8bN/L/KgT//xd3uJXtPOFa9ZWbNKivSWo4Nu6A1Cio5ayWDZfcklzbm8Df/TAk",
+	"90FaypJ6jn095E0ia5ec55nfDKnPNNdVrRUoZ+n8MzXwewPWfa+5AP/FMecXFgx+zLVyoBx+ZHUtRc6c",
+	"0OrwN6tVt1QY4HT+gRbCWPeOVUAzKln/kS2BXmbU5iVUzO+zBPzPtTXQORXKwRIMvc1GGwyPrTNCLent",
+	"aMvpw9vb24xysLkRNbJH5xT5J04TxjkufmmAOTiuxU/Q7iDWwGxtdA3GRcWoTczZXNfhlVU2WI4fLHEl",
+	"c8SVQD5CSyrWkhpMoU2VEThYHpDGgrHz3DNJMyocVDZJJ37BjGEtRanHBlBB4ZGXy/5tvfgNckcTWjo+",
+	"PfEcOU0i8duMnjKXlzvaf1BUJdTpSFdPsjXVRauj0MwFuz97SrN7dYMdJPZ+UQiQ3KLUTc2j1Bf+095i",
+	"P4aQuwfdbupwmhioJcshbm9rrWyQ6HvGz0J6uEMttdELCdXfpur5fwMFndP/OxwyzmF4ag9Pw6oUW+cl",
+	"kJiVyDWzRKgrJkWIZK0KKfIHZ8fWkItCACcGrG5MDp6zQjeKk0Xjo9uE7xjJOyZvM/pam4XgHNRDc5wz",
+	"KcEQYYnSDhNOJZwDjuaO2cdnJPRfzwUy+yMw6cq9IuAuDuN2IUG/0+41auu/xHKoFG89lPtc67dMtdHV",
+	"7SOZCm5yAA6cCGeJYQ6IFJVANs2aqTJaAuNgPKdnzMEbfHHm/51WoRhKljAp9TXurwgji8ZYR8eVeT1N",
+	"3Wajvc+gYkJhDtq8v4TC4ebIbN4YA8rtT8ZCQgQLuVbckkY5If3+QTXCkqKRskXbOm2Ab6UEzrSz48KB",
+	"2YWKghvXpyLRa/BuKkjnQrHGldqIT8AfK3VKln/EdOSTJ1kAMz7bfwQf6xcKbmrIHfAfjNHmIZlsetIE",
+	"PG3PTm10DtayhYQflBOufSy9CUuuQcoZ5kgI2T1nChPGAmPVGEwmunHeoeLeHjf3+HIVFgRsxY/dCjhA",
+	"vDFzwtfrCQKIS75vE07a+HJOdEHckDs8woyrOqSZ2ljwjYjjwnY8rhLEZwS9GZSL+ifXwpUdmQxLCn6u",
+	"hGo8gN1Nxo1oujZQiJspIwtYCoUpqJO9Jw5S4h+WsJoZlyJm4Ep/3M8GA6j/g4BccBql7GXqdx2bOBt5",
+	"yBS1ZdGv3gjrpr7Vs9Z/uCsAwk7bGfd7pVh52e05IIU1Xy8h/5j2I9RzZznvU/7djFyXIi99V+TDKy99",
+	"ft3NRtClrlVS12UbgqNjF2O6UaVnuv0uPGON9fwwUjAhgQd+8E2tZEukXi6Bp4hK5kDl7dtEw8ebUKGn",
+	"cmJdrISUIhaZFRF1s5Aj+VRTLUKf0MXIKhX8to//TsTYS3Lm2ILZtEc75hq7G2p7H97d1GSGp2NdZCPb",
+	"J10n+nifJJmUPxd0/mFHl133tI+QSI5dj92BEAnM+BRhQfHu23/Njk9PZj9BSwKKmqpqTWgkNRXpEtE9",
+	"tpJ9/Vxl0LeZyaxRgbWrY5CN7R1uMSxI6XVTJN6DseMWm6m+72mAaipcEkOMZrQPt9H6QQVdKZ5YMD4g",
+	"HBwT0hJmCYdCKCzELTl7/ZJ8+/zF3+n6fCG8Pt0ObmrJVIjJ2AnkoV5hnOcBpeZ9NEVckQqeyJD3XM4F",
+	"7sjkeObhTAPrsMI60+SuMcB9YBK20KFXDMAnxiz+ffKq46ES1mKR69oVmlB/GGFMxY2dcjfiiDtGYJMR",
+	"URCm2vGI6S7fGDn3pGJkVCjrmMoT6eni7IQYKCBo1iMTwUE57MLsds33abExYtbvky7qLOK/da0jiCQV",
+	"y0uhYGaAcf+F1znJNYeo+Iv3P5z9+u7n819f/3zx7lWahFfdCZ9SGSzW65dJ61ONG1JN7ClnJ69itvHp",
+	"6PdGOyDXJShioNbGocG3OOAQ0Kt8/Hh+fkrCQy8bzXaZQDnhZMJ2ttTGEdtUFTPtmm2I3yXbhIL2dYPJ",
+	"vrsafi1LdVx5gUalKXpHKnudAeNCgbUJALMyG98pTtZBUSJY7i8ZZ2MOU7J1M8zkaHLfSeQd7cJuA0qP",
+	"gfeaUgYJ7gPtek0kjIFN/cvG2BRyzP33nePjm6RmS8gIW/i41mrAdfhgq3duwtLoFJA3Rrj2PfIbzeTR",
+	"znETCrpAjnqMErAg7dHLQJr1sD40+d368NfrLqz+8c/zbnABdB6fDruUztWhNRaq0FPVHBMrqloCwWMD",
+	"p0nFFFtCOLzo4y/OlI9PT2hGr8DYsPbJwdHBETKoa1CsFnROn/mvMlozV3rZD1ktZtjF4R/L1BToDFxj",
+	"lCVwBaYl8fQiI7G3I9oQpV0Wiqi2jhjIQTnZ9o2xd0TquQg4HfM6RV8LMNPStfn306Oje5uGjtq4O85i",
+	"pPCMBx5QY8+PnmzauWf1cGXc5Bc9275oGE3jiqffbl+xPif1UhSskW4XFlfnTV4HtbYJO4dWwRKmOiP7",
+	"GSgjFsyVyOGAnHc43xLjnQJ46Np83fXfBroHE2uvnAVmo9PPdrMMowPSw5X1t1/QX1YbpoTLdF7d6WjV",
+	"a462m2R0uvO/7Wi32ZBdDj8Lfht8ToKDVJbBdLLifdhQo4OhCwq1lAjXD4h/EYEb61MQvlz60wUCRQG5",
+	"m/pf2L73v5oZVoHzs/wPaYg5OqYNdGgWSgOmzqEw+FI7lJ7QjkzG1H2Zupy47vNEzu9dy8v3kG5y9Hz7",
+	"iv5M6RH9quz7775m1QZy5gYjpKtYWNgh94g4+gz3qt8Fc1rBrgIqOcRmpv2EUzNtgegrMEzKbhPhYjth",
+	"p34XwekXTFjDcd+6yFHUcYJ6cfTsAQiPVNqdhHoF/oeGH7AbnX+4zOjNzBmmbLjuQecFkxa8c0hxBZ+2",
+	"4pnrElwJpuuJcrA2HDaJK0B4s2SGS/xSF/5skEMNioPKBdiJnd94ko9i5hT/D6XpCpwR+Xbs6MFheHct",
+	"5rqe/dToCu3RWOIQ/8e2dF3PbyPBrZrGTQ5rycSajie3mNbVmWbzoRQaEs1evtspkilOgOVlyl/xKM2/",
+	"CMQZVhQiT1RIT/kLuvDQ/2/PGX2+uM+MtQ8D1jE/H8qILRuHnwjX1wrbHamtI2zQ74PltdD0bfMN/xap",
+	"8EJZN99aiitQpBDSgbEZ0Qp8G00YyhFPeabd2UXsMe8ES765I/5sxOlIgizaDiz93oBpB7Q0nklsjsks",
+	"eRi6Kw3J/giJSihRNRVhy1Ui5C9C5bKx4gr+uoFgJdTxcpXc1lFgggF284cZYDf3wICfXiNpq40jizYj",
+	"4fgUeDh4/oXOfqG+JcR1oLg/EzZhTJJiC/dZYao7rhgOXjM6G/8xdo/ZhvnVbG2WldHZ6kjrDhtHFYeT",
+	"PsyTIVY8xMfg2eRR/obPWJK4E50/OTrKOt/xFzC363kYhA2TXrgSurHdbCvFQxiR0f16i/vL3f10MHWx",
+	"xIJJTVC+9sK7Dl0IIwquvTdOMnF3L3xLHn7LsHs24AyWe8uKOK/puOqui4R83d/zHN0oCWW3lqwN9xUl",
+	"c2BIf82sw0Os6q+/rN5HCcMijjcKWhJ19R1h3Q6rtBCsMMJF4c8bHFloHhn4zSvugGbpQewJh6rW/vg7",
+	"jmNXovINqCX2hU9fvPBx2f39JEuHy55jqM4Yt1841DaG2Z8kwo52iLCXo/u6z58+3YWv6WW1RxxB+Nqx",
+	"da71yn9vCfPRnZxpTUI+rNkl6sOoqokXyiMPDzan8nQD0a9DqknCvxOk7+MOr4Xi+ztDAS4vv5AvPEqy",
+	"++pbIzCB3V3i8N//nsYSqysY3UbZx9mGHyLt5W3xNz335m571uWB66+V+Z79e/9S/uABsTYymQmrJet/",
+	"XWAEk+ITYgYkUzfJrOx/ibVfWh79eO3PFCojtr/GytdY2Rwra4PJzyvXXD5c4mBjfHHmwyW6o58xJ4Pg",
+	"jc6ZDDNoDJjGyHj7ZX54KPFZqa2bf3P0zRFe8v33AK1QM/YvPQAA",
 }
 
 // GetSwagger returns the content of the embedded swagger specification file
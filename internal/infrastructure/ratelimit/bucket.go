@@ -0,0 +1,84 @@
+package ratelimit
+
+import (
+	"math"
+	"time"
+)
+
+// Limit allows bursts of Burst requests, refilled at Burst per Period.
+type Limit struct {
+	Burst  int
+	Period time.Duration
+}
+
+func (l Limit) unlimited() bool {
+	return l.Burst <= 0
+}
+
+// interval is the time it takes to refill one token.
+func (l Limit) interval() time.Duration {
+	return l.Period / time.Duration(l.Burst)
+}
+
+// Decision is the outcome of taking a token for a request.
+type Decision struct {
+	Allowed bool
+	Limit   Limit
+	// Remaining is the number of requests allowed right after this one.
+	Remaining int
+	// Reset is the time until the bucket is full again.
+	Reset time.Duration
+	// RetryAfter is the time until the next request is allowed, if this one
+	// was not.
+	RetryAfter time.Duration
+}
+
+// bucket is the state of a token bucket, which stores share.
+type bucket struct {
+	Tokens  float64   `json:"tokens"`
+	Updated time.Time `json:"updated"`
+}
+
+// take refills b for the time elapsed since it was updated, then takes a
+// token if one is left. A zero bucket is full.
+func (b *bucket) take(limit Limit, now time.Time) Decision {
+	b.refill(limit, now)
+	allowed := b.Tokens >= 1
+	if allowed {
+		b.Tokens--
+	}
+	return b.decide(limit, allowed)
+}
+
+// peek refills b like take, and tells whether a token is left without taking
+// it.
+func (b *bucket) peek(limit Limit, now time.Time) Decision {
+	b.refill(limit, now)
+	return b.decide(limit, b.Tokens >= 1)
+}
+
+func (b *bucket) refill(limit Limit, now time.Time) {
+	capacity := float64(limit.Burst)
+	if b.Updated.IsZero() {
+		b.Tokens = capacity
+	} else if elapsed := now.Sub(b.Updated); elapsed > 0 {
+		b.Tokens = math.Min(capacity, b.Tokens+float64(elapsed)/float64(limit.interval()))
+	}
+	b.Updated = now
+}
+
+func (b *bucket) decide(limit Limit, allowed bool) Decision {
+	d := Decision{Limit: limit, Allowed: allowed}
+	if !allowed {
+		d.RetryAfter = time.Duration((1 - b.Tokens) * float64(limit.interval()))
+	}
+	d.Remaining = int(b.Tokens)
+	d.Reset = time.Duration((float64(limit.Burst) - b.Tokens) * float64(limit.interval()))
+	return d
+}
+
+// full reports whether b will have refilled by now, so that forgetting it
+// makes no difference.
+func (b *bucket) full(limit Limit, now time.Time) bool {
+	return now.Sub(b.Updated) >= limit.Period
+}
@@ -0,0 +1,91 @@
+package ratelimit
+
+import (
+	"context"
+	"time"
+
+	"github.com/Jiei-S/boilerplate-clean-architecture/internal/infrastructure/config"
+)
+
+// sweepInterval is how often buckets that have refilled are forgotten.
+const sweepInterval = time.Minute
+
+// authFailuresKey prefixes the buckets of failed authentications, which are
+// kept apart from those of operations.
+const authFailuresKey = "auth-failures|"
+
+// Limiter limits the requests of each client to each operation.
+type Limiter struct {
+	store        Store
+	fallback     Limit
+	operations   map[string]Limit
+	authFailures Limit
+}
+
+// Allow takes a token for a request of client to operation. An operation
+// without limit is always allowed, with a zero Decision.Limit.
+func (l *Limiter) Allow(ctx context.Context, operation, client string) (Decision, error) {
+	limit, ok := l.operations[operation]
+	if !ok {
+		limit = l.fallback
+	}
+	if limit.unlimited() {
+		return Decision{Allowed: true}, nil
+	}
+	return l.store.Take(ctx, operation+"|"+client, limit, time.Now())
+}
+
+// AllowAuthAttempt tells whether client, which is not authenticated yet,
+// has failed to authenticate less often than the limit allows.
+func (l *Limiter) AllowAuthAttempt(ctx context.Context, client string) (Decision, error) {
+	if l.authFailures.unlimited() {
+		return Decision{Allowed: true}, nil
+	}
+	return l.store.Peek(ctx, authFailuresKey+client, l.authFailures, time.Now())
+}
+
+// RecordAuthFailure counts a failed authentication of client.
+func (l *Limiter) RecordAuthFailure(ctx context.Context, client string) error {
+	if l.authFailures.unlimited() {
+		return nil
+	}
+	_, err := l.store.Take(ctx, authFailuresKey+client, l.authFailures, time.Now())
+	return err
+}
+
+// Run sweeps the store until ctx is done, so that it only holds the buckets
+// of recent clients.
+func (l *Limiter) Run(ctx context.Context) {
+	ticker := time.NewTicker(sweepInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			l.store.Sweep(ctx, now)
+		}
+	}
+}
+
+// New returns a limiter that applies the limits of cfg, or none if it is
+// disabled.
+func New(cfg config.RateLimitConfig, store Store) *Limiter {
+	l := &Limiter{
+		store:      store,
+		operations: map[string]Limit{},
+	}
+	if !cfg.Enabled {
+		return l
+	}
+	l.fallback = toLimit(cfg.Default)
+	l.authFailures = toLimit(cfg.AuthFailures)
+	for operation, limit := range cfg.Operations {
+		l.operations[operation] = toLimit(limit)
+	}
+	return l
+}
+
+func toLimit(cfg config.LimitConfig) Limit {
+	return Limit{Burst: cfg.Limit, Period: cfg.Period}
+}
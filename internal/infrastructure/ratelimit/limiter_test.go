@@ -0,0 +1,222 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/Jiei-S/boilerplate-clean-architecture/internal/infrastructure/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBucket(t *testing.T) {
+	limit := Limit{Burst: 3, Period: 3 * time.Second}
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	var b bucket
+	for i := 2; i >= 0; i-- {
+		d := b.take(limit, now)
+		assert.True(t, d.Allowed)
+		assert.Equal(t, i, d.Remaining)
+	}
+	d := b.take(limit, now)
+	assert.False(t, d.Allowed)
+	assert.Equal(t, time.Second, d.RetryAfter)
+	assert.Equal(t, 3*time.Second, d.Reset)
+
+	// Half a token has come back.
+	d = b.take(limit, now.Add(500*time.Millisecond))
+	assert.False(t, d.Allowed)
+	assert.Equal(t, 500*time.Millisecond, d.RetryAfter)
+
+	d = b.take(limit, now.Add(time.Second))
+	assert.True(t, d.Allowed)
+	assert.Equal(t, 0, d.Remaining)
+
+	// The bucket does not refill beyond its burst.
+	d = b.take(limit, now.Add(time.Hour))
+	assert.True(t, d.Allowed)
+	assert.Equal(t, 2, d.Remaining)
+	assert.Equal(t, time.Second, d.Reset)
+}
+
+func TestStores(t *testing.T) {
+	stores := map[string]func() Store{
+		"memory": func() Store { return NewMemoryStore() },
+		"shared": func() Store { return NewSharedStore(NewLocalBackend()) },
+	}
+	limit := Limit{Burst: 2, Period: time.Minute}
+	for name, newStore := range stores {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			store := newStore()
+			now := time.Now()
+
+			for _, allowed := range []bool{true, true, false} {
+				d, err := store.Take(ctx, "a", limit, now)
+				require.NoError(t, err)
+				assert.Equal(t, allowed, d.Allowed)
+			}
+			// Keys have their own buckets.
+			d, err := store.Take(ctx, "b", limit, now)
+			require.NoError(t, err)
+			assert.True(t, d.Allowed)
+
+			// Sweeping forgets only the buckets that have refilled.
+			store.Sweep(ctx, now)
+			d, err = store.Take(ctx, "a", limit, now)
+			require.NoError(t, err)
+			assert.False(t, d.Allowed)
+
+			store.Sweep(ctx, now.Add(2*limit.Period))
+			d, err = store.Take(ctx, "a", limit, now.Add(2*limit.Period))
+			require.NoError(t, err)
+			assert.True(t, d.Allowed)
+			assert.Equal(t, 1, d.Remaining)
+		})
+	}
+}
+
+// countingBackend counts the writes to a LocalBackend.
+type countingBackend struct {
+	*LocalBackend
+	updates int
+}
+
+func (b *countingBackend) Update(ctx context.Context, key string, ttl time.Duration, update func(value []byte) ([]byte, error)) error {
+	b.updates++
+	return b.LocalBackend.Update(ctx, key, ttl, update)
+}
+
+func TestPeek(t *testing.T) {
+	limit := Limit{Burst: 2, Period: time.Minute}
+	backend := &countingBackend{LocalBackend: NewLocalBackend()}
+	stores := map[string]Store{
+		"memory": NewMemoryStore(),
+		"shared": NewSharedStore(backend),
+	}
+	for name, store := range stores {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			now := time.Now()
+
+			// Peeking at a client without a bucket leaves it without one.
+			d, err := store.Peek(ctx, "a", limit, now)
+			require.NoError(t, err)
+			assert.True(t, d.Allowed)
+			assert.Equal(t, 2, d.Remaining)
+
+			for i := 0; i < 2; i++ {
+				_, err = store.Take(ctx, "a", limit, now)
+				require.NoError(t, err)
+			}
+			// Peeking does not take a token.
+			for i := 0; i < 2; i++ {
+				d, err = store.Peek(ctx, "a", limit, now.Add(30*time.Second))
+				require.NoError(t, err)
+				assert.True(t, d.Allowed)
+				assert.Equal(t, 1, d.Remaining)
+			}
+			d, err = store.Take(ctx, "a", limit, now.Add(30*time.Second))
+			require.NoError(t, err)
+			assert.True(t, d.Allowed)
+			assert.Equal(t, 0, d.Remaining)
+		})
+	}
+	assert.Equal(t, 3, backend.updates, "only takes write to the backend")
+	assert.Len(t, backend.entries, 1)
+}
+
+func TestLimiter(t *testing.T) {
+	ctx := context.Background()
+	cfg := config.RateLimitConfig{
+		Enabled: true,
+		Default: config.LimitConfig{Limit: 1, Period: time.Minute},
+		Operations: map[string]config.LimitConfig{
+			"livez":   {},
+			"addUser": {Limit: 2, Period: time.Minute},
+		},
+	}
+
+	t.Run("limits each operation and client", func(t *testing.T) {
+		l := New(cfg, NewMemoryStore())
+		for _, allowed := range []bool{true, true, false} {
+			d, err := l.Allow(ctx, "addUser", "ip:192.0.2.1")
+			require.NoError(t, err)
+			assert.Equal(t, allowed, d.Allowed)
+			assert.Equal(t, 2, d.Limit.Burst)
+		}
+		d, err := l.Allow(ctx, "addUser", "ip:192.0.2.2")
+		require.NoError(t, err)
+		assert.True(t, d.Allowed)
+
+		// The default limit applies to the other operations.
+		for _, allowed := range []bool{true, false} {
+			d, err := l.Allow(ctx, "findUsers", "ip:192.0.2.1")
+			require.NoError(t, err)
+			assert.Equal(t, allowed, d.Allowed)
+		}
+	})
+
+	t.Run("refuses attempts after too many failures", func(t *testing.T) {
+		failures := cfg
+		failures.AuthFailures = config.LimitConfig{Limit: 2, Period: time.Minute}
+		l := New(failures, NewMemoryStore())
+		for i := 0; i < 2; i++ {
+			d, err := l.AllowAuthAttempt(ctx, "ip:192.0.2.1")
+			require.NoError(t, err)
+			assert.True(t, d.Allowed)
+			require.NoError(t, l.RecordAuthFailure(ctx, "ip:192.0.2.1"))
+		}
+		d, err := l.AllowAuthAttempt(ctx, "ip:192.0.2.1")
+		require.NoError(t, err)
+		assert.False(t, d.Allowed)
+		assert.InDelta(t, 30*time.Second, d.RetryAfter, float64(time.Second))
+
+		// Failures do not count against the operations.
+		d, err = l.Allow(ctx, "addUser", "ip:192.0.2.1")
+		require.NoError(t, err)
+		assert.True(t, d.Allowed)
+	})
+
+	t.Run("checks attempts without writing to a shared store", func(t *testing.T) {
+		failures := cfg
+		failures.AuthFailures = config.LimitConfig{Limit: 2, Period: time.Minute}
+		backend := &countingBackend{LocalBackend: NewLocalBackend()}
+		l := New(failures, NewSharedStore(backend))
+		for i := 0; i < 5; i++ {
+			d, err := l.AllowAuthAttempt(ctx, "ip:192.0.2.1")
+			require.NoError(t, err)
+			assert.True(t, d.Allowed)
+		}
+		assert.Zero(t, backend.updates)
+
+		require.NoError(t, l.RecordAuthFailure(ctx, "ip:192.0.2.1"))
+		d, err := l.AllowAuthAttempt(ctx, "ip:192.0.2.1")
+		require.NoError(t, err)
+		assert.Equal(t, 1, d.Remaining)
+		assert.Equal(t, 1, backend.updates)
+	})
+
+	t.Run("leaves operations without limit alone", func(t *testing.T) {
+		l := New(cfg, NewMemoryStore())
+		for i := 0; i < 5; i++ {
+			d, err := l.Allow(ctx, "livez", "ip:192.0.2.1")
+			require.NoError(t, err)
+			assert.True(t, d.Allowed)
+			assert.Zero(t, d.Limit.Burst)
+		}
+	})
+
+	t.Run("limits nothing when disabled", func(t *testing.T) {
+		disabled := cfg
+		disabled.Enabled = false
+		l := New(disabled, NewMemoryStore())
+		for i := 0; i < 5; i++ {
+			d, err := l.Allow(ctx, "addUser", "ip:192.0.2.1")
+			require.NoError(t, err)
+			assert.True(t, d.Allowed)
+		}
+	})
+}
@@ -0,0 +1,143 @@
+package ratelimit
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"time"
+)
+
+// Backend is a key-value store shared by the instances of the service, such
+// as Redis, that updates a value atomically.
+type Backend interface {
+	// Get returns the value of key, nil if there is none.
+	Get(ctx context.Context, key string) ([]byte, error)
+	// Update replaces the value of key, nil if there is none, with the one
+	// update returns, which expires after ttl. Concurrent updates of the
+	// same key must not interleave.
+	Update(ctx context.Context, key string, ttl time.Duration, update func(value []byte) ([]byte, error)) error
+}
+
+var _ Store = (*SharedStore)(nil)
+
+// SharedStore keeps buckets in a Backend, so that all instances limit the
+// requests of a client together. Buckets expire once they have refilled.
+type SharedStore struct {
+	backend Backend
+}
+
+func (s *SharedStore) Take(ctx context.Context, key string, limit Limit, now time.Time) (Decision, error) {
+	return s.update(ctx, key, limit, func(b *bucket) Decision { return b.take(limit, now) })
+}
+
+// Peek only reads the bucket, so that checking a client neither creates nor
+// extends the bucket of every client that is checked.
+func (s *SharedStore) Peek(ctx context.Context, key string, limit Limit, now time.Time) (Decision, error) {
+	value, err := s.backend.Get(ctx, key)
+	if err != nil {
+		return Decision{}, err
+	}
+	b, err := decodeBucket(value)
+	if err != nil {
+		return Decision{}, err
+	}
+	return b.peek(limit, now), nil
+}
+
+func (s *SharedStore) update(ctx context.Context, key string, limit Limit, decide func(b *bucket) Decision) (Decision, error) {
+	var d Decision
+	err := s.backend.Update(ctx, key, limit.Period, func(value []byte) ([]byte, error) {
+		b, err := decodeBucket(value)
+		if err != nil {
+			return nil, err
+		}
+		d = decide(b)
+		return json.Marshal(b)
+	})
+	return d, err
+}
+
+// decodeBucket decodes a stored bucket, or returns a full one for nil.
+func decodeBucket(value []byte) (*bucket, error) {
+	var b bucket
+	if value == nil {
+		return &b, nil
+	}
+	if err := json.Unmarshal(value, &b); err != nil {
+		return nil, err
+	}
+	return &b, nil
+}
+
+// Sweep sweeps the backend if it does not expire keys by itself.
+func (s *SharedStore) Sweep(ctx context.Context, now time.Time) {
+	if sweeper, ok := s.backend.(interface {
+		Sweep(ctx context.Context, now time.Time)
+	}); ok {
+		sweeper.Sweep(ctx, now)
+	}
+}
+
+func NewSharedStore(backend Backend) *SharedStore {
+	return &SharedStore{
+		backend: backend,
+	}
+}
+
+var _ Backend = (*LocalBackend)(nil)
+
+// LocalBackend is an in-process Backend that stands in for a shared one,
+// e.g. in development and tests. Instances using it do not share anything.
+type LocalBackend struct {
+	mu      sync.Mutex
+	entries map[string]localEntry
+}
+
+type localEntry struct {
+	value   []byte
+	expires time.Time
+}
+
+func (b *LocalBackend) Get(_ context.Context, key string) ([]byte, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if e, ok := b.entries[key]; ok && time.Now().Before(e.expires) {
+		return e.value, nil
+	}
+	return nil, nil
+}
+
+func (b *LocalBackend) Update(_ context.Context, key string, ttl time.Duration, update func(value []byte) ([]byte, error)) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	var value []byte
+	if e, ok := b.entries[key]; ok && time.Now().Before(e.expires) {
+		value = e.value
+	}
+	value, err := update(value)
+	if err != nil {
+		return err
+	}
+	b.entries[key] = localEntry{value: value, expires: time.Now().Add(ttl)}
+	return nil
+}
+
+// Sweep removes the entries that expired by now.
+func (b *LocalBackend) Sweep(_ context.Context, now time.Time) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for key, e := range b.entries {
+		if !now.Before(e.expires) {
+			delete(b.entries, key)
+		}
+	}
+}
+
+func NewLocalBackend() *LocalBackend {
+	return &LocalBackend{
+		entries: map[string]localEntry{},
+	}
+}
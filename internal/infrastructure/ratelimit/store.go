@@ -0,0 +1,75 @@
+package ratelimit
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Store keeps the token buckets of the clients.
+type Store interface {
+	// Take takes a token from the bucket of key for a request at now.
+	Take(ctx context.Context, key string, limit Limit, now time.Time) (Decision, error)
+	// Peek tells whether the bucket of key has a token left at now, without
+	// taking it.
+	Peek(ctx context.Context, key string, limit Limit, now time.Time) (Decision, error)
+	// Sweep forgets the buckets that have refilled by now.
+	Sweep(ctx context.Context, now time.Time)
+}
+
+var _ Store = (*MemoryStore)(nil)
+
+// MemoryStore keeps buckets in memory, so that each instance limits the
+// requests it receives on its own.
+type MemoryStore struct {
+	mu      sync.Mutex
+	buckets map[string]*memoryBucket
+}
+
+type memoryBucket struct {
+	bucket
+	limit Limit
+}
+
+func (s *MemoryStore) Take(_ context.Context, key string, limit Limit, now time.Time) (Decision, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	b, ok := s.buckets[key]
+	if !ok {
+		b = &memoryBucket{}
+		s.buckets[key] = b
+	}
+	b.limit = limit
+	return b.take(limit, now), nil
+}
+
+func (s *MemoryStore) Peek(_ context.Context, key string, limit Limit, now time.Time) (Decision, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	b, ok := s.buckets[key]
+	if !ok {
+		// Peeking must not fill the store with the buckets of every client.
+		return (&bucket{}).peek(limit, now), nil
+	}
+	b.limit = limit
+	return b.peek(limit, now), nil
+}
+
+func (s *MemoryStore) Sweep(_ context.Context, now time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for key, b := range s.buckets {
+		if b.full(b.limit, now) {
+			delete(s.buckets, key)
+		}
+	}
+}
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		buckets: map[string]*memoryBucket{},
+	}
+}
@@ -0,0 +1,37 @@
+package tracing
+
+import (
+	"context"
+
+	"github.com/Jiei-S/boilerplate-clean-architecture/internal/infrastructure/logging"
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	semconv "go.opentelemetry.io/otel/semconv/v1.21.0"
+)
+
+// redactingExporter masks user names in the error messages of spans before
+// they leave the process, as the logger does in log records. It covers the
+// spans of instrumentation that records raw driver errors, such as the SQL
+// spans of bunotel.
+type redactingExporter struct {
+	sdktrace.SpanExporter
+}
+
+func (e redactingExporter) ExportSpans(ctx context.Context, spans []sdktrace.ReadOnlySpan) error {
+	stubs := tracetest.SpanStubsFromReadOnlySpans(spans)
+	for i := range stubs {
+		stubs[i].Status.Description = logging.RedactString(stubs[i].Status.Description)
+		for j, event := range stubs[i].Events {
+			attrs := make([]attribute.KeyValue, len(event.Attributes))
+			for k, attr := range event.Attributes {
+				if attr.Key == semconv.ExceptionMessageKey {
+					attr = attr.Key.String(logging.RedactString(attr.Value.AsString()))
+				}
+				attrs[k] = attr
+			}
+			stubs[i].Events[j].Attributes = attrs
+		}
+	}
+	return e.SpanExporter.ExportSpans(ctx, stubs.Snapshots())
+}
@@ -0,0 +1,33 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	semconv "go.opentelemetry.io/otel/semconv/v1.21.0"
+)
+
+func TestRedactingExporter(t *testing.T) {
+	exporter := tracetest.NewInMemoryExporter()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSyncer(redactingExporter{exporter}))
+
+	// The SQL spans of bunotel record the error of the driver as is.
+	err := errors.New("Error 1062 (23000): Duplicate entry 'Taro-Yamada' for key 'users.users_name_unique'")
+	_, span := provider.Tracer("test").Start(context.Background(), "INSERT")
+	span.RecordError(err)
+	span.SetStatus(codes.Error, err.Error())
+	span.End()
+
+	redacted := "Error 1062 (23000): Duplicate entry 'xxxxx' for key 'users.users_name_unique'"
+	spans := exporter.GetSpans()
+	require.Len(t, spans, 1)
+	assert.Equal(t, redacted, spans[0].Status.Description)
+	require.Len(t, spans[0].Events, 1)
+	assert.Contains(t, spans[0].Events[0].Attributes, semconv.ExceptionMessage(redacted))
+}
@@ -0,0 +1,20 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/Jiei-S/boilerplate-clean-architecture/internal/domain/entity"
+	pkgErr "github.com/Jiei-S/boilerplate-clean-architecture/pkg/error"
+)
+
+type APIKeyRepository interface {
+	Save(ctx context.Context, e *entity.APIKey) (*entity.APIKey, *pkgErr.ApplicationError)
+	// FindByHash returns the key, revoked or not, whose hash is hash.
+	FindByHash(ctx context.Context, hash string) (*entity.APIKey, *pkgErr.ApplicationError)
+	// List returns every key, the most recently created first.
+	List(ctx context.Context) ([]*entity.APIKey, *pkgErr.ApplicationError)
+	// Revoke marks the key revoked now, unless it already is.
+	Revoke(ctx context.Context, id string) *pkgErr.ApplicationError
+	// Touch records that the key was used now.
+	Touch(ctx context.Context, id string) *pkgErr.ApplicationError
+}
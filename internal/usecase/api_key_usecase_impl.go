@@ -0,0 +1,123 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"github.com/Jiei-S/boilerplate-clean-architecture/internal/domain/entity"
+	pkgErr "github.com/Jiei-S/boilerplate-clean-architecture/pkg/error"
+	"github.com/Jiei-S/boilerplate-clean-architecture/pkg/tracing"
+)
+
+// lastUsedResolution is how stale the last use of a key may get before it is
+// recorded again, so that busy keys do not write on every request.
+const lastUsedResolution = time.Minute
+
+var _ APIKeyUsecase = (*APIKeyUsecaseImpl)(nil)
+
+type APIKeyUsecaseImpl struct {
+	apiKeyRepository APIKeyRepository
+	policy           *Policy
+}
+
+func (u *APIKeyUsecaseImpl) CreateAPIKey(
+	ctx context.Context,
+	dto *APIKeyInput,
+) (_ *CreatedAPIKey, err *pkgErr.ApplicationError) {
+	ctx, span := tracing.Start(ctx, "APIKeyUsecase.CreateAPIKey")
+	defer func() { tracing.End(span, err) }()
+
+	if err := u.policy.Authorize(ctx, ActionCreateAPIKey, ""); err != nil {
+		return nil, err
+	}
+	principal, _ := PrincipalFrom(ctx)
+
+	// Unknown scopes are rejected as invalid before any is checked against
+	// the permissions of the caller, which could not grant them either.
+	entity, key, err := dto.ToEntity(principal.Subject)
+	if err != nil {
+		return nil, err
+	}
+	// A caller cannot grant more than it may do itself.
+	for _, scope := range entity.Scopes {
+		if err := u.policy.Authorize(ctx, Action(scope), ""); err != nil {
+			return nil, pkgErr.NewApplicationError("scope exceeds the permissions of the caller", pkgErr.LevelWarn, pkgErr.CodeForbidden).
+				WithDetail("scope", scope)
+		}
+	}
+
+	entity, err = u.apiKeyRepository.Save(ctx, entity)
+	if err != nil {
+		return nil, err
+	}
+	return &CreatedAPIKey{
+		APIKey: *FromAPIKeyEntity(entity),
+		Key:    key,
+	}, nil
+}
+
+func (u *APIKeyUsecaseImpl) ListAPIKeys(
+	ctx context.Context,
+) (_ []*APIKey, err *pkgErr.ApplicationError) {
+	ctx, span := tracing.Start(ctx, "APIKeyUsecase.ListAPIKeys")
+	defer func() { tracing.End(span, err) }()
+
+	if err := u.policy.Authorize(ctx, ActionListAPIKeys, ""); err != nil {
+		return nil, err
+	}
+
+	entities, err := u.apiKeyRepository.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return FromAPIKeyEntities(entities), nil
+}
+
+func (u *APIKeyUsecaseImpl) RevokeAPIKey(
+	ctx context.Context,
+	id string,
+) (err *pkgErr.ApplicationError) {
+	ctx, span := tracing.Start(ctx, "APIKeyUsecase.RevokeAPIKey")
+	defer func() { tracing.End(span, err) }()
+
+	if err := u.policy.Authorize(ctx, ActionRevokeAPIKey, ""); err != nil {
+		return err
+	}
+	return u.apiKeyRepository.Revoke(ctx, id)
+}
+
+func (u *APIKeyUsecaseImpl) AuthenticateAPIKey(
+	ctx context.Context,
+	key string,
+) (_ *Principal, err *pkgErr.ApplicationError) {
+	ctx, span := tracing.Start(ctx, "APIKeyUsecase.AuthenticateAPIKey")
+	defer func() { tracing.End(span, err) }()
+
+	found, err := u.apiKeyRepository.FindByHash(ctx, entity.HashAPIKey(key))
+	switch {
+	case err != nil && err.Code() == pkgErr.CodeNotFound:
+		return nil, pkgErr.NewApplicationError("invalid API key", pkgErr.LevelInfo, pkgErr.CodeUnauthorized)
+	case err != nil:
+		return nil, err
+	case found.Revoked():
+		return nil, pkgErr.NewApplicationError("revoked API key", pkgErr.LevelInfo, pkgErr.CodeUnauthorized)
+	}
+
+	if found.LastUsedAt == nil || time.Since(*found.LastUsedAt) > lastUsedResolution {
+		// Failing to record the use must not fail the request.
+		if err := u.apiKeyRepository.Touch(ctx, found.ID); err != nil {
+			tracing.RecordError(span, err)
+		}
+	}
+	return apiKeyPrincipal(found), nil
+}
+
+func NewAPIKeyUsecase(
+	apiKeyRepository APIKeyRepository,
+	policy *Policy,
+) APIKeyUsecase {
+	return &APIKeyUsecaseImpl{
+		apiKeyRepository: apiKeyRepository,
+		policy:           policy,
+	}
+}
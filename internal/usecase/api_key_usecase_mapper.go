@@ -0,0 +1,96 @@
+package usecase
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/Jiei-S/boilerplate-clean-architecture/internal/domain/entity"
+	pkgErr "github.com/Jiei-S/boilerplate-clean-architecture/pkg/error"
+)
+
+// apiKeySubjectPrefix keeps the subjects of API keys apart from those of
+// users.
+const apiKeySubjectPrefix = "api-key:"
+
+type APIKeyInput struct {
+	Name   string
+	Scopes []string
+}
+
+type APIKey struct {
+	ID         string
+	Name       string
+	Prefix     string
+	Scopes     []string
+	CreatedBy  string
+	CreatedAt  time.Time
+	LastUsedAt *time.Time
+	RevokedAt  *time.Time
+}
+
+// CreatedAPIKey is a new API key along with the key in the clear, which
+// cannot be retrieved again.
+type CreatedAPIKey struct {
+	APIKey
+	Key string
+}
+
+// ToEntity generates a key for the input, whose scopes must be actions. The
+// problems of the scopes are reported along with those of the other fields.
+func (i *APIKeyInput) ToEntity(createdBy string) (*entity.APIKey, string, *pkgErr.ApplicationError) {
+	var fields []pkgErr.FieldError
+	for n, scope := range i.Scopes {
+		if !knownActions[Action(scope)] {
+			fields = append(fields, pkgErr.FieldError{
+				Field:   fmt.Sprintf("scopes[%d]", n),
+				Message: fmt.Sprintf("unknown scope %q", scope),
+			})
+		}
+	}
+	e, key, err := entity.NewAPIKey(i.Name, i.Scopes, createdBy)
+	if err != nil && err.Reason() != pkgErr.ReasonValidationFailed {
+		return nil, "", err
+	}
+	if err != nil {
+		fields = append(err.Fields(), fields...)
+	}
+	if len(fields) > 0 {
+		return nil, "", pkgErr.NewValidationError(fields)
+	}
+	return e, key, nil
+}
+
+func FromAPIKeyEntity(
+	e *entity.APIKey,
+) *APIKey {
+	return &APIKey{
+		ID:         e.ID,
+		Name:       e.Name,
+		Prefix:     e.Prefix,
+		Scopes:     e.Scopes,
+		CreatedBy:  e.CreatedBy,
+		CreatedAt:  e.CreatedAt,
+		LastUsedAt: e.LastUsedAt,
+		RevokedAt:  e.RevokedAt,
+	}
+}
+
+func FromAPIKeyEntities(
+	entities []*entity.APIKey,
+) []*APIKey {
+	keys := make([]*APIKey, 0, len(entities))
+	for _, e := range entities {
+		keys = append(keys, FromAPIKeyEntity(e))
+	}
+	return keys
+}
+
+// apiKeyPrincipal is the principal of a service authenticated by e, which
+// may perform the actions of its scopes.
+func apiKeyPrincipal(e *entity.APIKey) *Principal {
+	return &Principal{
+		Subject: apiKeySubjectPrefix + e.ID,
+		Scopes:  e.Scopes,
+		APIKey:  true,
+	}
+}
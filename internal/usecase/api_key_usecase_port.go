@@ -0,0 +1,15 @@
+package usecase
+
+import (
+	"context"
+
+	pkgErr "github.com/Jiei-S/boilerplate-clean-architecture/pkg/error"
+)
+
+type APIKeyUsecase interface {
+	CreateAPIKey(ctx context.Context, dto *APIKeyInput) (*CreatedAPIKey, *pkgErr.ApplicationError)
+	ListAPIKeys(ctx context.Context) ([]*APIKey, *pkgErr.ApplicationError)
+	RevokeAPIKey(ctx context.Context, id string) *pkgErr.ApplicationError
+	// AuthenticateAPIKey returns the principal of a valid, unrevoked key.
+	AuthenticateAPIKey(ctx context.Context, key string) (*Principal, *pkgErr.ApplicationError)
+}
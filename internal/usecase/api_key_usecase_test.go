@@ -0,0 +1,82 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Jiei-S/boilerplate-clean-architecture/internal/domain/entity"
+	pkgErr "github.com/Jiei-S/boilerplate-clean-architecture/pkg/error"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeAPIKeyRepository only saves keys.
+type fakeAPIKeyRepository struct {
+	APIKeyRepository
+	saved []*entity.APIKey
+}
+
+func (r *fakeAPIKeyRepository) Save(_ context.Context, e *entity.APIKey) (*entity.APIKey, *pkgErr.ApplicationError) {
+	r.saved = append(r.saved, e)
+	return e, nil
+}
+
+func TestCreateAPIKeyScopes(t *testing.T) {
+	policy, err := NewPolicy(map[string][]Action{
+		"admin":    {ActionCreateUser, ActionDeleteUser, ActionCreateAPIKey},
+		"operator": {ActionCreateAPIKey, ActionReadUser},
+	}, nil)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name      string
+		principal *Principal
+		keyName   string
+		scopes    []string
+		want      pkgErr.ErrorCode
+		fields    []pkgErr.FieldError
+	}{
+		{name: "within roles", principal: &Principal{Subject: "u1", Roles: []string{"admin"}}, scopes: []string{"users:delete"}},
+		{name: "beyond roles", principal: &Principal{Subject: "u1", Roles: []string{"operator"}}, scopes: []string{"users:read", "users:delete"}, want: pkgErr.CodeForbidden},
+		{name: "within key", principal: &Principal{Subject: "api-key:1", Scopes: []string{"apikeys:create"}, APIKey: true}, scopes: []string{"apikeys:create"}},
+		{name: "wider than key", principal: &Principal{Subject: "api-key:1", Scopes: []string{"apikeys:create"}, APIKey: true}, scopes: []string{"users:delete"}, want: pkgErr.CodeForbidden},
+		{name: "revoking from key", principal: &Principal{Subject: "api-key:1", Scopes: []string{"apikeys:create"}, APIKey: true}, scopes: []string{"apikeys:revoke"}, want: pkgErr.CodeForbidden},
+		{
+			name: "unknown scope", principal: &Principal{Subject: "u1", Roles: []string{"admin"}}, scopes: []string{"users:delete", "users:fly"},
+			want: pkgErr.CodeBadRequest, fields: []pkgErr.FieldError{{Field: "scopes[1]", Message: `unknown scope "users:fly"`}},
+		},
+		{
+			name: "unknown scope beyond roles", principal: &Principal{Subject: "u1", Roles: []string{"operator"}}, scopes: []string{"users:delete", "users:fly"},
+			want: pkgErr.CodeBadRequest, fields: []pkgErr.FieldError{{Field: "scopes[1]", Message: `unknown scope "users:fly"`}},
+		},
+		{
+			name: "unknown scope and no name", principal: &Principal{Subject: "u1", Roles: []string{"admin"}}, keyName: " ", scopes: []string{"users:fly"},
+			want: pkgErr.CodeBadRequest, fields: []pkgErr.FieldError{
+				{Field: "name", Message: "must not be empty"},
+				{Field: "scopes[0]", Message: `unknown scope "users:fly"`},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &fakeAPIKeyRepository{}
+			u := NewAPIKeyUsecase(repo, policy)
+			ctx := WithPrincipal(context.Background(), tt.principal)
+
+			keyName := tt.keyName
+			if keyName == "" {
+				keyName = "batch"
+			}
+			_, err := u.CreateAPIKey(ctx, &APIKeyInput{Name: keyName, Scopes: tt.scopes})
+			if tt.want == 0 {
+				assert.Nil(t, err)
+				assert.Len(t, repo.saved, 1)
+				return
+			}
+			require.NotNil(t, err)
+			assert.Equal(t, tt.want, err.Code())
+			assert.Equal(t, tt.fields, err.Fields())
+			assert.Empty(t, repo.saved)
+		})
+	}
+}
@@ -0,0 +1,136 @@
+package usecase
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"time"
+
+	"github.com/Jiei-S/boilerplate-clean-architecture/internal/domain/entity"
+	pkgErr "github.com/Jiei-S/boilerplate-clean-architecture/pkg/error"
+	"github.com/Jiei-S/boilerplate-clean-architecture/pkg/tracing"
+)
+
+// Idempotency replays the results of requests retried with the same
+// Idempotency-Key. A key is reserved before its request runs and its result
+// is saved in the same transaction, so that it is only kept if the request
+// succeeded, and the unique key makes a concurrent request with the same
+// key wait for the first one and fail, instead of running twice.
+type Idempotency struct {
+	repo          IdempotencyRepository
+	ttl           time.Duration
+	sweepInterval time.Duration
+}
+
+// idempotent returns the result of the earlier request of the caller with
+// key, or else reserves key, runs fn and remembers its result. request
+// identifies the request, so that a key reused for another request is
+// rejected. Without a key fn is simply run. ctx must carry a transaction.
+func idempotent[T any](
+	ctx context.Context,
+	i *Idempotency,
+	key string,
+	request any,
+	fn func(ctx context.Context) (T, *pkgErr.ApplicationError),
+) (_ T, err *pkgErr.ApplicationError) {
+	var zero T
+	if key == "" {
+		return fn(ctx)
+	}
+	if err := entity.ValidateIdempotencyKey(key); err != nil {
+		return zero, err
+	}
+	var subject string
+	if principal, ok := PrincipalFrom(ctx); ok {
+		subject = principal.Subject
+	}
+	fingerprint, err := fingerprintOf(request)
+	if err != nil {
+		return zero, err
+	}
+
+	found, err := i.repo.Find(ctx, subject, key)
+	switch {
+	case err == nil && !found.Expired(time.Now()):
+		if err := found.Replayable(fingerprint); err != nil {
+			return zero, err
+		}
+		var result T
+		if err := json.Unmarshal(found.Response, &result); err != nil {
+			return zero, pkgErr.Wrap(err, "failed to decode stored response", pkgErr.LevelError, pkgErr.CodeInternalServerError)
+		}
+		return result, nil
+	case err == nil:
+		// The key expired but has not been swept yet.
+		if err := i.repo.Delete(ctx, found.ID); err != nil {
+			return zero, err
+		}
+	case err.Code() != pkgErr.CodeNotFound:
+		return zero, err
+	}
+
+	record, err := entity.NewIdempotencyKey(key, subject, fingerprint, i.ttl)
+	if err != nil {
+		return zero, err
+	}
+	record, err = i.repo.Save(ctx, record)
+	if err != nil {
+		return zero, err
+	}
+
+	result, err := fn(ctx)
+	if err != nil {
+		return zero, err
+	}
+	response, jsonErr := json.Marshal(result)
+	if jsonErr != nil {
+		return zero, pkgErr.Wrap(jsonErr, "failed to encode response", pkgErr.LevelError, pkgErr.CodeInternalServerError)
+	}
+	if err := i.repo.SaveResponse(ctx, record.ID, response); err != nil {
+		return zero, err
+	}
+	return result, nil
+}
+
+func fingerprintOf(request any) (string, *pkgErr.ApplicationError) {
+	b, err := json.Marshal(request)
+	if err != nil {
+		return "", pkgErr.Wrap(err, "failed to encode request", pkgErr.LevelError, pkgErr.CodeInternalServerError)
+	}
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// Run deletes expired keys every sweep interval until ctx is done.
+func (i *Idempotency) Run(ctx context.Context) {
+	ticker := time.NewTicker(i.sweepInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			// Failures are recorded on the span; the next sweep retries.
+			i.sweep(ctx, now)
+		}
+	}
+}
+
+func (i *Idempotency) sweep(ctx context.Context, now time.Time) (err *pkgErr.ApplicationError) {
+	ctx, span := tracing.Start(ctx, "Idempotency.Sweep")
+	defer func() { tracing.End(span, err) }()
+
+	_, err = i.repo.DeleteExpired(ctx, now)
+	return err
+}
+
+// NewIdempotency remembers results for ttl, and deletes them every
+// sweepInterval once they have expired.
+func NewIdempotency(repo IdempotencyRepository, ttl, sweepInterval time.Duration) *Idempotency {
+	return &Idempotency{
+		repo:          repo,
+		ttl:           ttl,
+		sweepInterval: sweepInterval,
+	}
+}
@@ -0,0 +1,23 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"github.com/Jiei-S/boilerplate-clean-architecture/internal/domain/entity"
+	pkgErr "github.com/Jiei-S/boilerplate-clean-architecture/pkg/error"
+)
+
+type IdempotencyRepository interface {
+	// Find returns the key of subject, expired or not.
+	Find(ctx context.Context, subject, key string) (*entity.IdempotencyKey, *pkgErr.ApplicationError)
+	// Save fails with ReasonIdempotencyKeyInUse if subject already has the
+	// key.
+	Save(ctx context.Context, e *entity.IdempotencyKey) (*entity.IdempotencyKey, *pkgErr.ApplicationError)
+	// SaveResponse records the response to the request of the key.
+	SaveResponse(ctx context.Context, id string, response []byte) *pkgErr.ApplicationError
+	Delete(ctx context.Context, id string) *pkgErr.ApplicationError
+	// DeleteExpired deletes the keys expired by now, and returns how many
+	// there were.
+	DeleteExpired(ctx context.Context, now time.Time) (int, *pkgErr.ApplicationError)
+}
@@ -0,0 +1,144 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Jiei-S/boilerplate-clean-architecture/internal/domain/entity"
+	pkgErr "github.com/Jiei-S/boilerplate-clean-architecture/pkg/error"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeIdempotencyRepository keeps keys in a slice, without the unique
+// constraint of the real adapters.
+type fakeIdempotencyRepository struct {
+	keys []*entity.IdempotencyKey
+}
+
+func (r *fakeIdempotencyRepository) Find(_ context.Context, subject, key string) (*entity.IdempotencyKey, *pkgErr.ApplicationError) {
+	for _, k := range r.keys {
+		if k.Subject == subject && k.Key == key {
+			return k, nil
+		}
+	}
+	return nil, pkgErr.Wrap(sql.ErrNoRows, "resource not found", pkgErr.LevelWarn, pkgErr.CodeNotFound)
+}
+
+func (r *fakeIdempotencyRepository) Save(_ context.Context, e *entity.IdempotencyKey) (*entity.IdempotencyKey, *pkgErr.ApplicationError) {
+	e.ID = e.Subject + "|" + e.Key
+	r.keys = append(r.keys, e)
+	return e, nil
+}
+
+func (r *fakeIdempotencyRepository) SaveResponse(_ context.Context, id string, response []byte) *pkgErr.ApplicationError {
+	for _, k := range r.keys {
+		if k.ID == id {
+			k.Response = response
+		}
+	}
+	return nil
+}
+
+func (r *fakeIdempotencyRepository) Delete(_ context.Context, id string) *pkgErr.ApplicationError {
+	for i, k := range r.keys {
+		if k.ID == id {
+			r.keys = append(r.keys[:i], r.keys[i+1:]...)
+			break
+		}
+	}
+	return nil
+}
+
+func (r *fakeIdempotencyRepository) DeleteExpired(context.Context, time.Time) (int, *pkgErr.ApplicationError) {
+	return 0, nil
+}
+
+func TestIdempotent(t *testing.T) {
+	alice := WithPrincipal(context.Background(), &Principal{Subject: "alice"})
+	bob := WithPrincipal(context.Background(), &Principal{Subject: "bob"})
+
+	newRun := func() (*int, func(context.Context) (*User, *pkgErr.ApplicationError)) {
+		var calls int
+		return &calls, func(context.Context) (*User, *pkgErr.ApplicationError) {
+			calls++
+			return &User{ID: "u1", FirstName: "test", LastName: "user", Age: 20}, nil
+		}
+	}
+	request := &User{FirstName: "test", LastName: "user", Age: 20}
+
+	t.Run("without key runs every request", func(t *testing.T) {
+		i := NewIdempotency(&fakeIdempotencyRepository{}, time.Hour, time.Hour)
+		calls, run := newRun()
+		for n := 0; n < 2; n++ {
+			_, err := idempotent(alice, i, "", request, run)
+			require.Nil(t, err)
+		}
+		assert.Equal(t, 2, *calls)
+	})
+
+	t.Run("replays retries", func(t *testing.T) {
+		i := NewIdempotency(&fakeIdempotencyRepository{}, time.Hour, time.Hour)
+		calls, run := newRun()
+		first, err := idempotent(alice, i, "k1", request, run)
+		require.Nil(t, err)
+		retry := *request
+		second, err := idempotent(alice, i, "k1", &retry, run)
+		require.Nil(t, err)
+		assert.Equal(t, first, second)
+		assert.Equal(t, 1, *calls)
+	})
+
+	t.Run("rejects key reused for another request", func(t *testing.T) {
+		i := NewIdempotency(&fakeIdempotencyRepository{}, time.Hour, time.Hour)
+		calls, run := newRun()
+		_, err := idempotent(alice, i, "k1", request, run)
+		require.Nil(t, err)
+		other := *request
+		other.Age = 21
+		_, err = idempotent(alice, i, "k1", &other, run)
+		if assert.NotNil(t, err) {
+			assert.Equal(t, pkgErr.CodeUnprocessable, err.Code())
+			assert.Equal(t, entity.ReasonIdempotencyKeyReused, err.Reason())
+		}
+		assert.Equal(t, 1, *calls)
+	})
+
+	t.Run("keys are per caller", func(t *testing.T) {
+		i := NewIdempotency(&fakeIdempotencyRepository{}, time.Hour, time.Hour)
+		calls, run := newRun()
+		_, err := idempotent(alice, i, "k1", request, run)
+		require.Nil(t, err)
+		_, err = idempotent(bob, i, "k1", request, run)
+		require.Nil(t, err)
+		assert.Equal(t, 2, *calls)
+	})
+
+	t.Run("runs again once the key expired", func(t *testing.T) {
+		repo := &fakeIdempotencyRepository{}
+		i := NewIdempotency(repo, time.Hour, time.Hour)
+		calls, run := newRun()
+		_, err := idempotent(alice, i, "k1", request, run)
+		require.Nil(t, err)
+		repo.keys[0].ExpiresAt = time.Now().Add(-time.Second)
+
+		_, err = idempotent(alice, i, "k1", request, run)
+		require.Nil(t, err)
+		assert.Equal(t, 2, *calls)
+		require.Len(t, repo.keys, 1)
+		assert.False(t, repo.keys[0].Expired(time.Now()))
+	})
+
+	t.Run("rejects overlong key", func(t *testing.T) {
+		i := NewIdempotency(&fakeIdempotencyRepository{}, time.Hour, time.Hour)
+		_, run := newRun()
+		key := strings.Repeat("k", entity.IdempotencyKeyMaxLength+1)
+		_, err := idempotent(alice, i, key, request, run)
+		if assert.NotNil(t, err) {
+			assert.Equal(t, pkgErr.CodeBadRequest, err.Code())
+		}
+	})
+}
@@ -0,0 +1,122 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+
+	pkgErr "github.com/Jiei-S/boilerplate-clean-architecture/pkg/error"
+)
+
+// Action is something a principal may be permitted to do, named after the
+// resource it acts on, e.g. users:read.
+type Action string
+
+const (
+	ActionCreateUser Action = "users:create"
+	ActionReadUser   Action = "users:read"
+	ActionListUsers  Action = "users:list"
+	ActionUpdateUser Action = "users:update"
+	ActionDeleteUser Action = "users:delete"
+
+	ActionCreateAPIKey Action = "apikeys:create"
+	ActionListAPIKeys  Action = "apikeys:list"
+	ActionRevokeAPIKey Action = "apikeys:revoke"
+)
+
+var knownActions = map[Action]bool{
+	ActionCreateUser: true,
+	ActionReadUser:   true,
+	ActionListUsers:  true,
+	ActionUpdateUser: true,
+	ActionDeleteUser: true,
+
+	ActionCreateAPIKey: true,
+	ActionListAPIKeys:  true,
+	ActionRevokeAPIKey: true,
+}
+
+// Policy decides which actions a principal may perform. An API key may
+// perform the actions of its scopes. Any other principal may perform an
+// action if one of its roles grants it, or if it acts on the principal
+// itself and is granted to everyone on themselves; if the principal has
+// scopes, the action must be one of them too.
+type Policy struct {
+	roles map[string]map[Action]bool
+	self  map[Action]bool
+}
+
+// Authorize returns a forbidden error unless the principal of ctx may
+// perform action. owner is the subject that the action acts on, e.g. the ID
+// of the user to read, or empty if it does not act on a single subject.
+func (p *Policy) Authorize(ctx context.Context, action Action, owner string) *pkgErr.ApplicationError {
+	principal, ok := PrincipalFrom(ctx)
+	if !ok {
+		return pkgErr.NewApplicationError("authentication required", pkgErr.LevelWarn, pkgErr.CodeUnauthorized)
+	}
+	if !p.permits(principal, action, owner) {
+		return pkgErr.NewApplicationError("permission denied", pkgErr.LevelWarn, pkgErr.CodeForbidden).
+			WithDetail("action", string(action))
+	}
+	return nil
+}
+
+func (p *Policy) permits(principal *Principal, action Action, owner string) bool {
+	inScope := hasAction(principal.Scopes, action)
+	if principal.APIKey {
+		return inScope
+	}
+	if len(principal.Scopes) > 0 && !inScope {
+		return false
+	}
+	if owner != "" && owner == principal.Subject && p.self[action] {
+		return true
+	}
+	for _, role := range principal.Roles {
+		if p.roles[role][action] {
+			return true
+		}
+	}
+	return false
+}
+
+func hasAction(scopes []string, action Action) bool {
+	for _, scope := range scopes {
+		if Action(scope) == action {
+			return true
+		}
+	}
+	return false
+}
+
+// NewPolicy grants the actions of roles to the principals that have the
+// role, and the actions of self to every principal on itself.
+func NewPolicy(roles map[string][]Action, self []Action) (*Policy, error) {
+	p := &Policy{
+		roles: make(map[string]map[Action]bool, len(roles)),
+		self:  map[Action]bool{},
+	}
+	for role, actions := range roles {
+		granted, err := toActionSet(actions)
+		if err != nil {
+			return nil, fmt.Errorf("role %s: %w", role, err)
+		}
+		p.roles[role] = granted
+	}
+	granted, err := toActionSet(self)
+	if err != nil {
+		return nil, fmt.Errorf("self: %w", err)
+	}
+	p.self = granted
+	return p, nil
+}
+
+func toActionSet(actions []Action) (map[Action]bool, error) {
+	set := make(map[Action]bool, len(actions))
+	for _, action := range actions {
+		if !knownActions[action] {
+			return nil, fmt.Errorf("unknown action %q", action)
+		}
+		set[action] = true
+	}
+	return set, nil
+}
@@ -0,0 +1,61 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+
+	pkgErr "github.com/Jiei-S/boilerplate-clean-architecture/pkg/error"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPolicyAuthorize(t *testing.T) {
+	policy, err := NewPolicy(map[string][]Action{
+		"admin":  {ActionCreateUser, ActionReadUser},
+		"reader": {ActionReadUser},
+	}, []Action{ActionReadUser})
+	require.NoError(t, err)
+
+	tests := []struct {
+		name      string
+		principal *Principal
+		action    Action
+		owner     string
+		want      pkgErr.ErrorCode
+	}{
+		{name: "granted by role", principal: &Principal{Subject: "u1", Roles: []string{"admin"}}, action: ActionCreateUser},
+		{name: "granted by one of the roles", principal: &Principal{Subject: "u1", Roles: []string{"guest", "admin"}}, action: ActionCreateUser},
+		{name: "not granted by role", principal: &Principal{Subject: "u1", Roles: []string{"reader"}}, action: ActionCreateUser, want: pkgErr.CodeForbidden},
+		{name: "unknown role", principal: &Principal{Subject: "u1", Roles: []string{"guest"}}, action: ActionReadUser, owner: "u2", want: pkgErr.CodeForbidden},
+		{name: "granted by scope", principal: &Principal{Subject: "api-key:1", Scopes: []string{"users:create"}, APIKey: true}, action: ActionCreateUser},
+		{name: "not granted by scope", principal: &Principal{Subject: "api-key:1", Scopes: []string{"users:read"}, APIKey: true}, action: ActionCreateUser, want: pkgErr.CodeForbidden},
+		{name: "token scope does not widen roles", principal: &Principal{Subject: "u1", Roles: []string{"reader"}, Scopes: []string{"users:create"}}, action: ActionCreateUser, want: pkgErr.CodeForbidden},
+		{name: "token scope narrows roles", principal: &Principal{Subject: "u1", Roles: []string{"admin"}, Scopes: []string{"users:read"}}, action: ActionCreateUser, want: pkgErr.CodeForbidden},
+		{name: "token scope within roles", principal: &Principal{Subject: "u1", Roles: []string{"admin"}, Scopes: []string{"users:create"}}, action: ActionCreateUser},
+		{name: "self", principal: &Principal{Subject: "u1"}, action: ActionReadUser, owner: "u1"},
+		{name: "other user", principal: &Principal{Subject: "u1"}, action: ActionReadUser, owner: "u2", want: pkgErr.CodeForbidden},
+		{name: "self without self grant", principal: &Principal{Subject: "u1"}, action: ActionCreateUser, owner: "u1", want: pkgErr.CodeForbidden},
+		{name: "anonymous", action: ActionReadUser, owner: "u1", want: pkgErr.CodeUnauthorized},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			if tt.principal != nil {
+				ctx = WithPrincipal(ctx, tt.principal)
+			}
+
+			err := policy.Authorize(ctx, tt.action, tt.owner)
+			if tt.want == 0 {
+				assert.Nil(t, err)
+				return
+			}
+			require.NotNil(t, err)
+			assert.Equal(t, tt.want, err.Code())
+		})
+	}
+}
+
+func TestNewPolicyRejectsUnknownActions(t *testing.T) {
+	_, err := NewPolicy(map[string][]Action{"admin": {"users:fly"}}, nil)
+	assert.Error(t, err)
+}
@@ -0,0 +1,30 @@
+package usecase
+
+import "context"
+
+// Principal is the authenticated caller of a usecase, as asserted by a
+// verified token or an API key.
+type Principal struct {
+	// Subject identifies the caller, e.g. the ID of a user.
+	Subject string
+	Roles   []string
+	// Scopes are the actions granted to an API key. The scopes of a token
+	// only narrow what its roles grant.
+	Scopes []string
+	// APIKey marks a principal authenticated by an API key.
+	APIKey bool
+}
+
+type principalKey struct{}
+
+// WithPrincipal returns a copy of ctx carrying p.
+func WithPrincipal(ctx context.Context, p *Principal) context.Context {
+	return context.WithValue(ctx, principalKey{}, p)
+}
+
+// PrincipalFrom returns the principal of ctx, if the caller is
+// authenticated.
+func PrincipalFrom(ctx context.Context) (*Principal, bool) {
+	p, ok := ctx.Value(principalKey{}).(*Principal)
+	return p, ok && p != nil
+}
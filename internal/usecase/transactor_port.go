@@ -0,0 +1,15 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+
+	pkgErr "github.com/Jiei-S/boilerplate-clean-architecture/pkg/error"
+)
+
+// Transactor is the unit of work of the usecase layer. Repositories called
+// with the context passed to fn take part in the transaction, and RunInTx
+// called again with that context nests a savepoint in it.
+type Transactor interface {
+	RunInTx(ctx context.Context, opts *sql.TxOptions, fn func(ctx context.Context) *pkgErr.ApplicationError) *pkgErr.ApplicationError
+}
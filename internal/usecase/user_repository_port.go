@@ -2,6 +2,7 @@ package usecase
 
 import (
 	"context"
+	"time"
 
 	"github.com/Jiei-S/boilerplate-clean-architecture/internal/domain/entity"
 	pkgErr "github.com/Jiei-S/boilerplate-clean-architecture/pkg/error"
@@ -10,4 +11,40 @@ import (
 type UserRepository interface {
 	Save(ctx context.Context, e *entity.User) (*entity.User, *pkgErr.ApplicationError)
 	Find(ctx context.Context, id string) (*entity.User, *pkgErr.ApplicationError)
+	List(ctx context.Context, criteria *UserCriteria) ([]*entity.User, *pkgErr.ApplicationError)
+	Update(ctx context.Context, e *entity.User) (*entity.User, *pkgErr.ApplicationError)
+	Delete(ctx context.Context, id string) *pkgErr.ApplicationError
+}
+
+type UserSortField string
+
+const (
+	UserSortCreatedAt UserSortField = "createdAt"
+	UserSortFirstName UserSortField = "firstName"
+	UserSortLastName  UserSortField = "lastName"
+	UserSortAge       UserSortField = "age"
+)
+
+type UserSort struct {
+	Field UserSortField
+	Desc  bool
+}
+
+// UserCursor is the position of the last user of the previous page.
+// Users are ordered by (sort field, CreatedAt, ID), so Value holds the
+// sort field of that user and is unused when sorting by CreatedAt.
+type UserCursor struct {
+	Value     interface{}
+	CreatedAt time.Time
+	ID        string
+}
+
+type UserCriteria struct {
+	FirstName *string
+	LastName  *string
+	MinAge    *int32
+	MaxAge    *int32
+	Sort      UserSort
+	After     *UserCursor
+	Limit     int
 }
@@ -3,30 +3,65 @@ package usecase
 import (
 	"context"
 
+	"github.com/Jiei-S/boilerplate-clean-architecture/internal/domain/entity"
 	pkgErr "github.com/Jiei-S/boilerplate-clean-architecture/pkg/error"
+	"github.com/Jiei-S/boilerplate-clean-architecture/pkg/tracing"
 )
 
 var _ UserUsecase = (*UserUsecaseImpl)(nil)
 
 type UserUsecaseImpl struct {
 	userRepository UserRepository
+	transactor     Transactor
+	policy         *Policy
+	idempotency    *Idempotency
 }
 
 func (u *UserUsecaseImpl) AddUser(
 	ctx context.Context,
 	dto *User,
-) (*User, *pkgErr.ApplicationError) {
-	entity, err := u.userRepository.Save(ctx, dto.ToEntity())
+	idempotencyKey string,
+) (_ *User, err *pkgErr.ApplicationError) {
+	ctx, span := tracing.Start(ctx, "UserUsecase.AddUser")
+	defer func() { tracing.End(span, err) }()
+
+	if err := u.policy.Authorize(ctx, ActionCreateUser, ""); err != nil {
+		return nil, err
+	}
+
+	var added *User
+	err = u.transactor.RunInTx(ctx, nil, func(ctx context.Context) (err *pkgErr.ApplicationError) {
+		added, err = idempotent(ctx, u.idempotency, idempotencyKey, dto, func(ctx context.Context) (*User, *pkgErr.ApplicationError) {
+			entity, err := dto.ToEntity()
+			if err != nil {
+				return nil, err
+			}
+
+			entity, err = u.userRepository.Save(ctx, entity)
+			if err != nil {
+				return nil, err
+			}
+			return FromEntity(entity), nil
+		})
+		return err
+	})
 	if err != nil {
 		return nil, err
 	}
-	return FromEntity(entity), nil
+	return added, nil
 }
 
 func (u *UserUsecaseImpl) FindUser(
 	ctx context.Context,
 	id string,
-) (*User, *pkgErr.ApplicationError) {
+) (_ *User, err *pkgErr.ApplicationError) {
+	ctx, span := tracing.Start(ctx, "UserUsecase.FindUser")
+	defer func() { tracing.End(span, err) }()
+
+	if err := u.policy.Authorize(ctx, ActionReadUser, id); err != nil {
+		return nil, err
+	}
+
 	entity, err := u.userRepository.Find(ctx, id)
 	if err != nil {
 		return nil, err
@@ -34,10 +69,113 @@ func (u *UserUsecaseImpl) FindUser(
 	return FromEntity(entity), nil
 }
 
+func (u *UserUsecaseImpl) ListUsers(
+	ctx context.Context,
+	dto *UserListQuery,
+) (_ *UserList, err *pkgErr.ApplicationError) {
+	ctx, span := tracing.Start(ctx, "UserUsecase.ListUsers")
+	defer func() { tracing.End(span, err) }()
+
+	if err := u.policy.Authorize(ctx, ActionListUsers, ""); err != nil {
+		return nil, err
+	}
+
+	criteria, err := dto.ToCriteria()
+	if err != nil {
+		return nil, err
+	}
+
+	// Fetch one extra user to find out whether there is a next page.
+	limit := criteria.Limit
+	criteria.Limit++
+
+	entities, err := u.userRepository.List(ctx, criteria)
+	if err != nil {
+		return nil, err
+	}
+	return FromEntities(entities, criteria.Sort, limit), nil
+}
+
+func (u *UserUsecaseImpl) UpdateUser(
+	ctx context.Context,
+	id string,
+	dto *User,
+) (_ *User, err *pkgErr.ApplicationError) {
+	ctx, span := tracing.Start(ctx, "UserUsecase.UpdateUser")
+	defer func() { tracing.End(span, err) }()
+
+	if err := u.policy.Authorize(ctx, ActionUpdateUser, id); err != nil {
+		return nil, err
+	}
+
+	return u.modifyUser(ctx, id, dto.ApplyTo)
+}
+
+func (u *UserUsecaseImpl) PatchUser(
+	ctx context.Context,
+	id string,
+	dto *UserPatch,
+) (_ *User, err *pkgErr.ApplicationError) {
+	ctx, span := tracing.Start(ctx, "UserUsecase.PatchUser")
+	defer func() { tracing.End(span, err) }()
+
+	if err := u.policy.Authorize(ctx, ActionUpdateUser, id); err != nil {
+		return nil, err
+	}
+
+	return u.modifyUser(ctx, id, dto.ApplyTo)
+}
+
+func (u *UserUsecaseImpl) DeleteUser(
+	ctx context.Context,
+	id string,
+) (err *pkgErr.ApplicationError) {
+	ctx, span := tracing.Start(ctx, "UserUsecase.DeleteUser")
+	defer func() { tracing.End(span, err) }()
+
+	if err := u.policy.Authorize(ctx, ActionDeleteUser, id); err != nil {
+		return err
+	}
+
+	return u.userRepository.Delete(ctx, id)
+}
+
+// modifyUser reads the user and writes it back after apply in a single
+// transaction.
+func (u *UserUsecaseImpl) modifyUser(
+	ctx context.Context,
+	id string,
+	apply func(e *entity.User) *pkgErr.ApplicationError,
+) (*User, *pkgErr.ApplicationError) {
+	var updated *entity.User
+	err := u.transactor.RunInTx(ctx, nil, func(ctx context.Context) *pkgErr.ApplicationError {
+		found, err := u.userRepository.Find(ctx, id)
+		if err != nil {
+			return err
+		}
+		if err := apply(found); err != nil {
+			return err
+		}
+
+		updated, err = u.userRepository.Update(ctx, found)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return FromEntity(updated), nil
+}
+
 func NewUserUsecase(
 	userRepository UserRepository,
+	transactor Transactor,
+	policy *Policy,
+	idempotency *Idempotency,
 ) UserUsecase {
 	return &UserUsecaseImpl{
 		userRepository: userRepository,
+		transactor:     transactor,
+		policy:         policy,
+		idempotency:    idempotency,
 	}
 }
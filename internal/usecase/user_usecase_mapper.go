@@ -1,7 +1,18 @@
 package usecase
 
 import (
+	"encoding/base64"
+	"encoding/json"
+	"strings"
+	"time"
+
 	"github.com/Jiei-S/boilerplate-clean-architecture/internal/domain/entity"
+	pkgErr "github.com/Jiei-S/boilerplate-clean-architecture/pkg/error"
+)
+
+const (
+	DefaultUserListLimit = 20
+	MaxUserListLimit     = 100
 )
 
 type User struct {
@@ -11,12 +22,47 @@ type User struct {
 	Age       int32
 }
 
-func (u *User) ToEntity() *entity.User {
-	return &entity.User{
-		FirstName: u.FirstName,
-		LastName:  u.LastName,
-		Age:       u.Age,
+type UserPatch struct {
+	FirstName *string
+	LastName  *string
+	Age       *int32
+}
+
+type UserListQuery struct {
+	FirstName *string
+	LastName  *string
+	MinAge    *int32
+	MaxAge    *int32
+	Sort      string
+	Limit     int
+	Cursor    string
+}
+
+type UserList struct {
+	Users      []*User
+	NextCursor string
+}
+
+func (u *User) ToEntity() (*entity.User, *pkgErr.ApplicationError) {
+	return entity.NewUser(u.FirstName, u.LastName, u.Age)
+}
+
+func (u *User) ApplyTo(e *entity.User) *pkgErr.ApplicationError {
+	return e.Update(u.FirstName, u.LastName, u.Age)
+}
+
+func (u *UserPatch) ApplyTo(e *entity.User) *pkgErr.ApplicationError {
+	firstName, lastName, age := e.FirstName, e.LastName, e.Age
+	if u.FirstName != nil {
+		firstName = *u.FirstName
+	}
+	if u.LastName != nil {
+		lastName = *u.LastName
 	}
+	if u.Age != nil {
+		age = *u.Age
+	}
+	return e.Update(firstName, lastName, age)
 }
 
 func FromEntity(
@@ -29,3 +75,139 @@ func FromEntity(
 		Age:       entity.Age,
 	}
 }
+
+func FromEntities(
+	entities []*entity.User,
+	sort UserSort,
+	limit int,
+) *UserList {
+	list := &UserList{
+		Users: make([]*User, 0, len(entities)),
+	}
+	if len(entities) > limit {
+		entities = entities[:limit]
+		list.NextCursor = encodeUserCursor(sort, entities[limit-1])
+	}
+	for _, e := range entities {
+		list.Users = append(list.Users, FromEntity(e))
+	}
+	return list
+}
+
+func (q *UserListQuery) ToCriteria() (*UserCriteria, *pkgErr.ApplicationError) {
+	sort, err := parseUserSort(q.Sort)
+	if err != nil {
+		return nil, err
+	}
+	if q.MinAge != nil && q.MaxAge != nil && *q.MinAge > *q.MaxAge {
+		return nil, pkgErr.NewApplicationError("minAge must not be greater than maxAge", pkgErr.LevelWarn, pkgErr.CodeBadRequest)
+	}
+
+	limit := q.Limit
+	if limit == 0 {
+		limit = DefaultUserListLimit
+	}
+	if limit < 0 || limit > MaxUserListLimit {
+		return nil, pkgErr.NewApplicationError("limit is out of range", pkgErr.LevelWarn, pkgErr.CodeBadRequest)
+	}
+
+	criteria := &UserCriteria{
+		FirstName: q.FirstName,
+		LastName:  q.LastName,
+		MinAge:    q.MinAge,
+		MaxAge:    q.MaxAge,
+		Sort:      sort,
+		Limit:     limit,
+	}
+	if q.Cursor != "" {
+		if criteria.After, err = decodeUserCursor(q.Cursor, sort); err != nil {
+			return nil, err
+		}
+	}
+	return criteria, nil
+}
+
+func parseUserSort(s string) (UserSort, *pkgErr.ApplicationError) {
+	if s == "" {
+		return UserSort{Field: UserSortCreatedAt}, nil
+	}
+
+	sort := UserSort{
+		Field: UserSortField(strings.TrimPrefix(s, "-")),
+		Desc:  strings.HasPrefix(s, "-"),
+	}
+	switch sort.Field {
+	case UserSortCreatedAt, UserSortFirstName, UserSortLastName, UserSortAge:
+		return sort, nil
+	default:
+		return UserSort{}, pkgErr.NewApplicationError("invalid sort: "+s, pkgErr.LevelWarn, pkgErr.CodeBadRequest)
+	}
+}
+
+func (s UserSort) String() string {
+	if s.Desc {
+		return "-" + string(s.Field)
+	}
+	return string(s.Field)
+}
+
+// userCursor is the wire format of UserList.NextCursor. It records the sort
+// it was issued for so that a cursor cannot be reused with another order.
+type userCursor struct {
+	Sort      string          `json:"s"`
+	Value     json.RawMessage `json:"v,omitempty"`
+	CreatedAt time.Time       `json:"c"`
+	ID        string          `json:"i"`
+}
+
+func encodeUserCursor(sort UserSort, e *entity.User) string {
+	c := userCursor{
+		Sort:      sort.String(),
+		CreatedAt: e.CreatedAt,
+		ID:        e.ID,
+	}
+	switch sort.Field {
+	case UserSortFirstName:
+		c.Value, _ = json.Marshal(e.FirstName)
+	case UserSortLastName:
+		c.Value, _ = json.Marshal(e.LastName)
+	case UserSortAge:
+		c.Value, _ = json.Marshal(e.Age)
+	}
+
+	b, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+func decodeUserCursor(s string, sort UserSort) (*UserCursor, *pkgErr.ApplicationError) {
+	invalid := pkgErr.NewApplicationError("invalid cursor", pkgErr.LevelWarn, pkgErr.CodeBadRequest)
+
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, invalid
+	}
+	var c userCursor
+	if err := json.Unmarshal(b, &c); err != nil || c.Sort != sort.String() || c.ID == "" {
+		return nil, invalid
+	}
+
+	cursor := &UserCursor{
+		CreatedAt: c.CreatedAt,
+		ID:        c.ID,
+	}
+	switch sort.Field {
+	case UserSortFirstName, UserSortLastName:
+		var v string
+		if err := json.Unmarshal(c.Value, &v); err != nil {
+			return nil, invalid
+		}
+		cursor.Value = v
+	case UserSortAge:
+		var v int32
+		if err := json.Unmarshal(c.Value, &v); err != nil {
+			return nil, invalid
+		}
+		cursor.Value = v
+	}
+	return cursor, nil
+}
@@ -7,6 +7,12 @@ import (
 )
 
 type UserUsecase interface {
-	AddUser(ctx context.Context, dto *User) (*User, *pkgErr.ApplicationError)
+	// AddUser replays the user added by an earlier request of the caller
+	// with the same non-empty idempotencyKey.
+	AddUser(ctx context.Context, dto *User, idempotencyKey string) (*User, *pkgErr.ApplicationError)
 	FindUser(ctx context.Context, id string) (*User, *pkgErr.ApplicationError)
+	ListUsers(ctx context.Context, dto *UserListQuery) (*UserList, *pkgErr.ApplicationError)
+	UpdateUser(ctx context.Context, id string, dto *User) (*User, *pkgErr.ApplicationError)
+	PatchUser(ctx context.Context, id string, dto *UserPatch) (*User, *pkgErr.ApplicationError)
+	DeleteUser(ctx context.Context, id string) *pkgErr.ApplicationError
 }
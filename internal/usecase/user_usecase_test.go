@@ -0,0 +1,81 @@
+// Package usecase_test runs the use cases on the database adapters, which
+// import package usecase.
+package usecase_test
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Jiei-S/boilerplate-clean-architecture/internal/adapter/gateway"
+	"github.com/Jiei-S/boilerplate-clean-architecture/internal/domain/entity"
+	"github.com/Jiei-S/boilerplate-clean-architecture/internal/infrastructure/bun"
+	"github.com/Jiei-S/boilerplate-clean-architecture/internal/infrastructure/config"
+	"github.com/Jiei-S/boilerplate-clean-architecture/internal/usecase"
+	pkgErr "github.com/Jiei-S/boilerplate-clean-architecture/pkg/error"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAddUserConcurrentRetries(t *testing.T) {
+	ctx := context.Background()
+	// A database file with several connections lets the retries run
+	// concurrently. Transactions take the write lock when they begin and
+	// wait for each other, rather than failing to upgrade a read lock.
+	cfg := config.Default().Database
+	cfg.Driver = config.DriverSQLite
+	cfg.DSN = "file:" + filepath.Join(t.TempDir(), "test.db") + "?_pragma=busy_timeout(10000)&_txlock=immediate"
+	db, closeDB, err := bun.NewDB(cfg)
+	require.NoError(t, err)
+	t.Cleanup(closeDB)
+	db.SetMaxOpenConns(4)
+	migrator, err := bun.NewMigrator(db)
+	require.NoError(t, err)
+	_, err = bun.Migrate(ctx, migrator, cfg.MigrationLockTimeout)
+	require.NoError(t, err)
+
+	policy, err := usecase.NewPolicy(map[string][]usecase.Action{"admin": {usecase.ActionCreateUser}}, nil)
+	require.NoError(t, err)
+	users := gateway.NewUserRepository(db)
+	u := usecase.NewUserUsecase(
+		users,
+		gateway.NewTransactor(db, slog.New(slog.NewTextHandler(io.Discard, nil))),
+		policy,
+		usecase.NewIdempotency(gateway.NewIdempotencyRepository(db), time.Hour, time.Hour),
+	)
+	ctx = usecase.WithPrincipal(ctx, &usecase.Principal{Subject: "admin", Roles: []string{"admin"}})
+
+	const retries = 8
+	var (
+		wg    sync.WaitGroup
+		added [retries]*usecase.User
+		errs  [retries]*pkgErr.ApplicationError
+	)
+	for i := 0; i < retries; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			added[i], errs[i] = u.AddUser(ctx, &usecase.User{FirstName: "retried", LastName: "user", Age: 20}, "retry-1")
+		}(i)
+	}
+	wg.Wait()
+
+	created, listErr := users.List(ctx, &usecase.UserCriteria{Limit: retries + 1})
+	require.Nil(t, listErr)
+	require.Len(t, created, 1)
+
+	// Every retry either gets the user of the first request or is told that
+	// the key is in use; none adds another user or fails otherwise.
+	for i := 0; i < retries; i++ {
+		if errs[i] != nil {
+			assert.Equal(t, pkgErr.CodeConflict, errs[i].Code(), errs[i].Error())
+			assert.Equal(t, entity.ReasonIdempotencyKeyInUse, errs[i].Reason(), errs[i].Error())
+			continue
+		}
+		assert.Equal(t, created[0].ID, added[i].ID)
+	}
+}
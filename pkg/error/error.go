@@ -1,5 +1,13 @@
 package error
 
+import (
+	"errors"
+	"fmt"
+	"io"
+	"runtime"
+	"strings"
+)
+
 type ErrorLevel int8
 
 const (
@@ -9,6 +17,19 @@ const (
 	LevelError
 )
 
+var levelNames = map[ErrorLevel]string{
+	LevelInfo:  "info",
+	LevelWarn:  "warn",
+	LevelError: "error",
+}
+
+func (l ErrorLevel) String() string {
+	if name, ok := levelNames[l]; ok {
+		return name
+	}
+	return fmt.Sprintf("level(%d)", l)
+}
+
 type ErrorCode int
 
 const (
@@ -17,15 +38,113 @@ const (
 	CodeNotFound
 	CodeDuplicate
 	CodeInternalServerError
+	// CodeConflict is a request that conflicts with the current state of
+	// another resource, e.g. one that references a missing resource.
+	CodeConflict
+	// CodeUnavailable is a transient failure that may succeed if retried.
+	CodeUnavailable
+	// CodeUnauthorized is a request without valid credentials.
+	CodeUnauthorized
+	// CodeForbidden is a request of a caller that is not permitted to make it.
+	CodeForbidden
+	// CodeTooManyRequests is a request over the rate limit of its caller.
+	CodeTooManyRequests
+	// CodeUnprocessable is a well-formed request that cannot be carried
+	// out, e.g. one that reuses an idempotency key with another body.
+	CodeUnprocessable
+	// CodeRequestTooLarge is a request whose body exceeds the limit of the
+	// server.
+	CodeRequestTooLarge
+)
+
+var codeNames = map[ErrorCode]string{
+	CodeBadRequest:          "bad_request",
+	CodeNotFound:            "not_found",
+	CodeDuplicate:           "duplicate",
+	CodeInternalServerError: "internal",
+	CodeConflict:            "conflict",
+	CodeUnavailable:         "unavailable",
+	CodeUnauthorized:        "unauthorized",
+	CodeForbidden:           "forbidden",
+	CodeTooManyRequests:     "too_many_requests",
+	CodeUnprocessable:       "unprocessable",
+	CodeRequestTooLarge:     "request_too_large",
+}
+
+func (c ErrorCode) String() string {
+	if name, ok := codeNames[c]; ok {
+		return name
+	}
+	return fmt.Sprintf("code(%d)", c)
+}
+
+// Reason is a stable, machine-readable identifier of an error that clients
+// can branch on. ErrorCode only tells the kind of failure, while Reason
+// tells exactly what failed, e.g. USER_NOT_FOUND rather than NOT_FOUND.
+type Reason string
+
+const (
+	ReasonBadRequest       Reason = "BAD_REQUEST"
+	ReasonValidationFailed Reason = "VALIDATION_FAILED"
+	ReasonNotFound         Reason = "NOT_FOUND"
+	ReasonDuplicate        Reason = "DUPLICATE"
+	ReasonInternal         Reason = "INTERNAL"
+	ReasonConflict         Reason = "CONFLICT"
+	ReasonUnavailable      Reason = "UNAVAILABLE"
+	ReasonUnauthorized     Reason = "UNAUTHORIZED"
+	ReasonForbidden        Reason = "FORBIDDEN"
+	ReasonRateLimited      Reason = "RATE_LIMITED"
+	ReasonUnprocessable    Reason = "UNPROCESSABLE"
+	ReasonRequestTooLarge  Reason = "REQUEST_TOO_LARGE"
 )
 
+var defaultReasons = map[ErrorCode]Reason{
+	CodeBadRequest:          ReasonBadRequest,
+	CodeNotFound:            ReasonNotFound,
+	CodeDuplicate:           ReasonDuplicate,
+	CodeInternalServerError: ReasonInternal,
+	CodeConflict:            ReasonConflict,
+	CodeUnavailable:         ReasonUnavailable,
+	CodeUnauthorized:        ReasonUnauthorized,
+	CodeForbidden:           ReasonForbidden,
+	CodeTooManyRequests:     ReasonRateLimited,
+	CodeUnprocessable:       ReasonUnprocessable,
+	CodeRequestTooLarge:     ReasonRequestTooLarge,
+}
+
+type FieldError struct {
+	Field   string
+	Message string
+}
+
 type ApplicationError struct {
 	message string
 	level   ErrorLevel
 	code    ErrorCode
+	reason  Reason
+	fields  []FieldError
+	details map[string]interface{}
+	cause   error
+	stack   []uintptr
 }
 
 func (e *ApplicationError) Error() string {
+	msg := e.message
+	if len(e.fields) > 0 {
+		details := make([]string, 0, len(e.fields))
+		for _, f := range e.fields {
+			details = append(details, f.Field+" "+f.Message)
+		}
+		msg += ": " + strings.Join(details, ", ")
+	}
+	if e.cause != nil {
+		msg += ": " + e.cause.Error()
+	}
+	return msg
+}
+
+// Message returns the message of e without its fields and cause.
+func (e *ApplicationError) Message() string {
 	return e.message
 }
 
@@ -37,10 +156,108 @@ func (e *ApplicationError) Code() ErrorCode {
 	return e.code
 }
 
+func (e *ApplicationError) Reason() Reason {
+	return e.reason
+}
+
+func (e *ApplicationError) Fields() []FieldError {
+	return e.fields
+}
+
+func (e *ApplicationError) Details() map[string]interface{} {
+	return e.details
+}
+
+func (e *ApplicationError) Unwrap() error {
+	return e.cause
+}
+
+// Is reports whether target is an ApplicationError with the same reason, so
+// that errors.Is(err, pkgErr.NewApplicationError(...).WithReason(r)) matches
+// any error of reason r in the chain of err.
+func (e *ApplicationError) Is(target error) bool {
+	var t *ApplicationError
+	return errors.As(target, &t) && t.reason == e.reason
+}
+
+// StackTrace returns the frames of the call stack at which e was created.
+func (e *ApplicationError) StackTrace() []runtime.Frame {
+	frames := runtime.CallersFrames(e.stack)
+	result := make([]runtime.Frame, 0, len(e.stack))
+	for {
+		frame, more := frames.Next()
+		result = append(result, frame)
+		if !more {
+			return result
+		}
+	}
+}
+
+// Format prints the stack trace after the message with the %+v verb.
+func (e *ApplicationError) Format(s fmt.State, verb rune) {
+	switch {
+	case verb == 'v' && s.Flag('+'):
+		fmt.Fprintf(s, "%s [%s]", e.Error(), e.reason)
+		for _, frame := range e.StackTrace() {
+			fmt.Fprintf(s, "\n%s\n\t%s:%d", frame.Function, frame.File, frame.Line)
+		}
+	case verb == 'v' || verb == 's':
+		io.WriteString(s, e.Error())
+	case verb == 'q':
+		fmt.Fprintf(s, "%q", e.Error())
+	}
+}
+
+// WithReason returns a copy of e with reason in place of the default reason
+// derived from the error code. e itself is left untouched, so that errors
+// shared by several callers are not changed by one of them.
+func (e *ApplicationError) WithReason(reason Reason) *ApplicationError {
+	c := *e
+	c.reason = reason
+	return &c
+}
+
+// WithDetail returns a copy of e with structured data, such as the ID of the
+// missing resource, that is reported to clients along with the error. e
+// itself is left untouched.
+func (e *ApplicationError) WithDetail(key string, value interface{}) *ApplicationError {
+	c := *e
+	c.details = make(map[string]interface{}, len(e.details)+1)
+	for k, v := range e.details {
+		c.details[k] = v
+	}
+	c.details[key] = value
+	return &c
+}
+
 func NewApplicationError(message string, level ErrorLevel, code ErrorCode) *ApplicationError {
+	return newApplicationError(message, level, code, nil)
+}
+
+// Wrap creates an ApplicationError caused by err.
+func Wrap(err error, message string, level ErrorLevel, code ErrorCode) *ApplicationError {
+	return newApplicationError(message, level, code, err)
+}
+
+// NewValidationError reports every invalid field of an input at once.
+func NewValidationError(fields []FieldError) *ApplicationError {
+	e := newApplicationError("validation failed", LevelWarn, CodeBadRequest, nil)
+	e.reason = ReasonValidationFailed
+	e.fields = fields
+	return e
+}
+
+func newApplicationError(message string, level ErrorLevel, code ErrorCode, cause error) *ApplicationError {
+	var pcs [32]uintptr
+	// Skip runtime.Callers, this function and the exported constructor.
+	n := runtime.Callers(3, pcs[:])
+
 	return &ApplicationError{
 		message: message,
 		level:   level,
 		code:    code,
+		reason:  defaultReasons[code],
+		cause:   cause,
+		stack:   pcs[:n],
 	}
 }
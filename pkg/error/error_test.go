@@ -0,0 +1,102 @@
+package error
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWrap(t *testing.T) {
+	err := Wrap(sql.ErrNoRows, "resource not found", LevelWarn, CodeNotFound)
+
+	assert.Equal(t, "resource not found: sql: no rows in result set", err.Error())
+	assert.Equal(t, "resource not found", err.Message())
+	assert.Equal(t, LevelWarn, err.Level())
+	assert.Equal(t, CodeNotFound, err.Code())
+	assert.Equal(t, ReasonNotFound, err.Reason())
+	assert.Equal(t, sql.ErrNoRows, err.Unwrap())
+	assert.ErrorIs(t, err, sql.ErrNoRows)
+
+	assert.Nil(t, NewApplicationError("resource not found", LevelWarn, CodeNotFound).Unwrap())
+}
+
+func TestIs(t *testing.T) {
+	const reasonUserNotFound Reason = "USER_NOT_FOUND"
+	notFound := NewApplicationError("user not found", LevelWarn, CodeNotFound).WithReason(reasonUserNotFound)
+
+	err := Wrap(sql.ErrNoRows, "resource not found", LevelWarn, CodeNotFound).WithReason(reasonUserNotFound)
+	// Reasons match whatever the message, cause or position in the chain.
+	assert.ErrorIs(t, err, notFound)
+	assert.ErrorIs(t, fmt.Errorf("find user: %w", err), notFound)
+	assert.ErrorIs(t, Wrap(err, "failed to add user", LevelError, CodeInternalServerError), notFound)
+	assert.ErrorIs(t, fmt.Errorf("find user: %w", err), sql.ErrNoRows)
+
+	assert.NotErrorIs(t, Wrap(sql.ErrNoRows, "resource not found", LevelWarn, CodeNotFound), notFound)
+	assert.NotErrorIs(t, errors.New("user not found"), notFound)
+}
+
+func TestAs(t *testing.T) {
+	inner := Wrap(sql.ErrNoRows, "resource not found", LevelWarn, CodeNotFound)
+	err := fmt.Errorf("find user: %w", inner)
+
+	var appErr *ApplicationError
+	require.ErrorAs(t, err, &appErr)
+	assert.Same(t, inner, appErr)
+
+	assert.False(t, errors.As(errors.New("plain"), &appErr))
+}
+
+func TestBuildersCopy(t *testing.T) {
+	const reasonUserNotFound Reason = "USER_NOT_FOUND"
+	sentinel := NewApplicationError("user not found", LevelWarn, CodeNotFound).WithDetail("kind", "user")
+
+	withReason := sentinel.WithReason(reasonUserNotFound)
+	withDetail := sentinel.WithDetail("userId", "u1")
+	other := sentinel.WithDetail("userId", "u2")
+
+	assert.Equal(t, ReasonNotFound, sentinel.Reason())
+	assert.Equal(t, map[string]interface{}{"kind": "user"}, sentinel.Details())
+	assert.Equal(t, reasonUserNotFound, withReason.Reason())
+	assert.Equal(t, map[string]interface{}{"kind": "user", "userId": "u1"}, withDetail.Details())
+	assert.Equal(t, map[string]interface{}{"kind": "user", "userId": "u2"}, other.Details())
+	assert.Equal(t, sentinel.Message(), withDetail.Message())
+	assert.Equal(t, sentinel.StackTrace(), withDetail.StackTrace())
+}
+
+func TestValidationError(t *testing.T) {
+	err := NewValidationError([]FieldError{
+		{Field: "firstName", Message: "must not be empty"},
+		{Field: "age", Message: "must be between 0 and 150"},
+	})
+	assert.Equal(t, "validation failed: firstName must not be empty, age must be between 0 and 150", err.Error())
+	assert.Equal(t, CodeBadRequest, err.Code())
+	assert.Equal(t, ReasonValidationFailed, err.Reason())
+	assert.Len(t, err.Fields(), 2)
+}
+
+func TestFormat(t *testing.T) {
+	err := Wrap(errors.New(`no "rows"`), "resource not found", LevelWarn, CodeNotFound)
+
+	assert.Equal(t, `resource not found: no "rows"`, fmt.Sprintf("%v", err))
+	assert.Equal(t, `resource not found: no "rows"`, fmt.Sprintf("%s", err))
+	assert.Equal(t, `"resource not found: no \"rows\""`, fmt.Sprintf("%q", err))
+
+	verbose := fmt.Sprintf("%+v", err)
+	lines := strings.Split(verbose, "\n")
+	assert.Equal(t, `resource not found: no "rows" [NOT_FOUND]`, lines[0])
+	assert.Contains(t, lines[1], "TestFormat")
+	assert.Contains(t, lines[2], "error_test.go:")
+}
+
+func TestStackTrace(t *testing.T) {
+	err := NewApplicationError("boom", LevelError, CodeInternalServerError)
+	frames := err.StackTrace()
+	require.NotEmpty(t, frames)
+	// The first frame is the caller of the constructor.
+	assert.True(t, strings.HasSuffix(frames[0].Function, ".TestStackTrace"), frames[0].Function)
+}
@@ -0,0 +1,46 @@
+package tracing
+
+import (
+	"context"
+
+	pkgErr "github.com/Jiei-S/boilerplate-clean-architecture/pkg/error"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.21.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const instrumentationName = "github.com/Jiei-S/boilerplate-clean-architecture"
+
+// Start starts a span named name as a child of the span in ctx, with the
+// tracer provider installed globally.
+func Start(ctx context.Context, name string, opts ...trace.SpanStartOption) (context.Context, trace.Span) {
+	return otel.Tracer(instrumentationName).Start(ctx, name, opts...)
+}
+
+// End ends span, recording err on it if the operation failed. Only errors
+// of LevelError mark the span as failed; the others, such as a missing
+// user, are answers rather than faults.
+func End(span trace.Span, err *pkgErr.ApplicationError) {
+	if err != nil {
+		RecordError(span, err)
+	}
+	span.End()
+}
+
+// RecordError adds err to span as an exception event. Only the message of
+// err is recorded, not its cause, whose driver errors may quote personal
+// data.
+func RecordError(span trace.Span, err *pkgErr.ApplicationError) {
+	span.AddEvent(semconv.ExceptionEventName, trace.WithAttributes(
+		semconv.ExceptionType("ApplicationError"),
+		semconv.ExceptionMessage(err.Message()),
+		attribute.String("error.code", err.Code().String()),
+		attribute.String("error.level", err.Level().String()),
+		attribute.String("error.reason", string(err.Reason())),
+	))
+	if err.Level() == pkgErr.LevelError {
+		span.SetStatus(codes.Error, err.Message())
+	}
+}
@@ -0,0 +1,39 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	pkgErr "github.com/Jiei-S/boilerplate-clean-architecture/pkg/error"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	semconv "go.opentelemetry.io/otel/semconv/v1.21.0"
+)
+
+func TestRecordError(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	tracer := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)).Tracer("test")
+
+	cause := errors.New("Duplicate entry 'Taro-Yamada' for key 'users.users_name_unique'")
+	_, span := tracer.Start(context.Background(), "UserRepository.Save")
+	End(span, pkgErr.Wrap(cause, "resource already exists", pkgErr.LevelError, pkgErr.CodeDuplicate))
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 1)
+	require.Len(t, spans[0].Events(), 1)
+	event := spans[0].Events()[0]
+	assert.Equal(t, semconv.ExceptionEventName, event.Name)
+	assert.Contains(t, event.Attributes, semconv.ExceptionMessage("resource already exists"))
+	assert.Contains(t, event.Attributes, attribute.String("error.code", "duplicate"))
+	assert.Contains(t, event.Attributes, attribute.String("error.reason", "DUPLICATE"))
+	for _, attr := range event.Attributes {
+		assert.NotContains(t, attr.Value.Emit(), "Taro")
+	}
+	assert.Equal(t, codes.Error, spans[0].Status().Code)
+	assert.Equal(t, "resource already exists", spans[0].Status().Description)
+}
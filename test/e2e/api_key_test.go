@@ -0,0 +1,92 @@
+package controller_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/Jiei-S/boilerplate-clean-architecture/internal/adapter/gateway"
+	rest "github.com/Jiei-S/boilerplate-clean-architecture/internal/infrastructure/openapi"
+	"github.com/stretchr/testify/assert"
+)
+
+const API_KEYS_URL = "http://localhost:8081/api-keys"
+
+func TestAPIKey(t *testing.T) {
+	defer func() {
+		db := newDB(t)
+		db.NewTruncateTable().Model(&gateway.User{}).Exec(context.Background())
+		db.NewTruncateTable().Model(&gateway.APIKey{}).Exec(context.Background())
+	}()
+
+	r, err := http.Post(API_KEYS_URL, "application/json", strings.NewReader(`{"name":"batch","scopes":["users:create"]}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var created rest.CreatedApiKey
+	if err := json.NewDecoder(r.Body).Decode(&created); err != nil {
+		t.Fatal(err)
+	}
+	r.Body.Close()
+	assert.Equal(t, http.StatusOK, r.StatusCode)
+	assert.True(t, strings.HasPrefix(created.Key, created.Prefix))
+
+	withKey := func(method, url, body string) int {
+		t.Helper()
+		req, err := http.NewRequest(method, url, strings.NewReader(body))
+		if err != nil {
+			t.Fatal(err)
+		}
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("X-API-Key", created.Key)
+		resp, err := (&http.Client{}).Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		return resp.StatusCode
+	}
+
+	t.Run("key performs its scopes", func(t *testing.T) {
+		code := withKey(http.MethodPost, BASE_API_URL, `{"firstName":"api","lastName":"key","age":20}`)
+		assert.Equal(t, http.StatusOK, code)
+	})
+
+	t.Run("key is denied other actions", func(t *testing.T) {
+		assert.Equal(t, http.StatusForbidden, withKey(http.MethodGet, BASE_API_URL, ""))
+	})
+
+	t.Run("list records last use", func(t *testing.T) {
+		r, err := http.Get(API_KEYS_URL)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer r.Body.Close()
+		var list rest.ApiKeyList
+		if err := json.NewDecoder(r.Body).Decode(&list); err != nil {
+			t.Fatal(err)
+		}
+		if assert.Len(t, list.Items, 1) {
+			assert.Equal(t, created.Id, list.Items[0].Id)
+			assert.NotNil(t, list.Items[0].LastUsedAt)
+		}
+	})
+
+	t.Run("revoked key is rejected", func(t *testing.T) {
+		req, err := http.NewRequest(http.MethodDelete, API_KEYS_URL+"/"+created.Id, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		r, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		r.Body.Close()
+		assert.Equal(t, http.StatusNoContent, r.StatusCode)
+
+		code := withKey(http.MethodPost, BASE_API_URL, `{"firstName":"api","lastName":"revoked","age":20}`)
+		assert.Equal(t, http.StatusUnauthorized, code)
+	})
+}
@@ -0,0 +1,152 @@
+package controller_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Jiei-S/boilerplate-clean-architecture/internal/adapter/gateway"
+	"github.com/Jiei-S/boilerplate-clean-architecture/internal/infrastructure/auth"
+	"github.com/Jiei-S/boilerplate-clean-architecture/internal/infrastructure/config"
+	rest "github.com/Jiei-S/boilerplate-clean-architecture/internal/infrastructure/openapi"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestMain authenticates the requests of http.DefaultClient with a token
+// signed by the secret the server under test is configured with.
+func TestMain(m *testing.M) {
+	cfg, _, err := config.Load(nil)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	token, err := auth.Sign(cfg.Auth, "e2e", []string{"admin"}, nil, time.Hour)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	http.DefaultClient.Transport = bearerTransport{token: token}
+	os.Exit(m.Run())
+}
+
+type bearerTransport struct {
+	token string
+}
+
+func (t bearerTransport) RoundTrip(r *http.Request) (*http.Response, error) {
+	r = r.Clone(r.Context())
+	r.Header.Set("Authorization", "Bearer "+t.token)
+	return http.DefaultTransport.RoundTrip(r)
+}
+
+func TestAuthentication(t *testing.T) {
+	anonymous := &http.Client{}
+
+	tests := []struct {
+		name          string
+		url           string
+		authorization string
+		code          int
+	}{
+		{name: "missing token", url: BASE_API_URL, code: http.StatusUnauthorized},
+		{name: "malformed header", url: BASE_API_URL, authorization: "Basic dXNlcjpwYXNz", code: http.StatusUnauthorized},
+		{name: "invalid token", url: BASE_API_URL, authorization: "Bearer not-a-token", code: http.StatusUnauthorized},
+		{name: "public endpoint", url: baseURL + "/livez", code: http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, err := http.NewRequest(http.MethodGet, tt.url, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if tt.authorization != "" {
+				req.Header.Set("Authorization", tt.authorization)
+			}
+			resp, err := anonymous.Do(req)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer resp.Body.Close()
+
+			assert.Equal(t, tt.code, resp.StatusCode)
+			if tt.code != http.StatusUnauthorized {
+				return
+			}
+			assert.Contains(t, resp.Header.Get("WWW-Authenticate"), "Bearer")
+			var problem rest.Problem
+			if err := json.NewDecoder(resp.Body).Decode(&problem); err != nil {
+				t.Fatal(err)
+			}
+			assert.Equal(t, "UNAUTHORIZED", problem.Reason)
+		})
+	}
+}
+
+func TestAuthorization(t *testing.T) {
+	defer func() {
+		db := newDB(t)
+		db.NewTruncateTable().Model(&gateway.User{}).Exec(context.Background())
+	}()
+
+	r, err := http.Post(BASE_API_URL, "application/json", strings.NewReader(`{"firstName":"authz","lastName":"user","age":20}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var user rest.User
+	if err := json.NewDecoder(r.Body).Decode(&user); err != nil {
+		t.Fatal(err)
+	}
+	r.Body.Close()
+
+	cfg, _, err := config.Load(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		subject string
+		roles   []string
+		method  string
+		url     string
+		code    int
+	}{
+		{name: "reader lists", subject: "someone", roles: []string{"reader"}, method: http.MethodGet, url: BASE_API_URL, code: http.StatusOK},
+		{name: "reader deletes", subject: "someone", roles: []string{"reader"}, method: http.MethodDelete, url: BASE_API_URL + "/" + user.Id, code: http.StatusForbidden},
+		{name: "user reads itself", subject: user.Id, method: http.MethodGet, url: BASE_API_URL + "/" + user.Id, code: http.StatusOK},
+		{name: "user reads another", subject: "someone", method: http.MethodGet, url: BASE_API_URL + "/" + user.Id, code: http.StatusForbidden},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			token, err := auth.Sign(cfg.Auth, tt.subject, tt.roles, nil, time.Minute)
+			if err != nil {
+				t.Fatal(err)
+			}
+			req, err := http.NewRequest(tt.method, tt.url, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			req.Header.Set("Authorization", "Bearer "+token)
+			resp, err := (&http.Client{}).Do(req)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer resp.Body.Close()
+
+			assert.Equal(t, tt.code, resp.StatusCode)
+			if tt.code != http.StatusForbidden {
+				return
+			}
+			var problem rest.Problem
+			if err := json.NewDecoder(resp.Body).Decode(&problem); err != nil {
+				t.Fatal(err)
+			}
+			assert.Equal(t, "FORBIDDEN", problem.Reason)
+		})
+	}
+}
@@ -0,0 +1,51 @@
+package controller_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	rest "github.com/Jiei-S/boilerplate-clean-architecture/internal/infrastructure/openapi"
+	"github.com/stretchr/testify/assert"
+)
+
+var baseURL = strings.TrimSuffix(BASE_API_URL, "/users")
+
+func TestLivez(t *testing.T) {
+	resp, err := http.Get(baseURL + "/livez")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+
+	var got rest.Health
+	if err := json.NewDecoder(resp.Body).Decode(&got); err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, rest.Healthy, got.Status)
+}
+
+func TestReadyz(t *testing.T) {
+	resp, err := http.Get(baseURL + "/readyz")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+
+	var got rest.Readiness
+	if err := json.NewDecoder(resp.Body).Decode(&got); err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, rest.Healthy, got.Status)
+
+	names := make([]string, 0, len(got.Components))
+	for _, component := range got.Components {
+		assert.Equal(t, rest.Healthy, component.Status, component.Name)
+		assert.GreaterOrEqual(t, component.LatencyMs, 0.0)
+		names = append(names, component.Name)
+	}
+	assert.Equal(t, []string{"server", "database"}, names)
+}
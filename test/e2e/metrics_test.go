@@ -0,0 +1,31 @@
+package controller_test
+
+import (
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMetrics(t *testing.T) {
+	if _, err := http.Get(BASE_API_URL + "?limit=1"); err != nil {
+		t.Fatal(err)
+	}
+
+	resp, err := http.Get(baseURL + "/metrics")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Contains(t, string(body), `app_http_requests_total{method="GET",operation="listUsers",status="200"}`)
+	assert.Contains(t, string(body), "app_http_request_duration_seconds_bucket")
+	assert.Contains(t, string(body), `app_db_query_duration_seconds_count{operation="SELECT",result="success"}`)
+	assert.Contains(t, string(body), "go_sql_open_connections")
+}
@@ -0,0 +1,54 @@
+package controller_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	rest "github.com/Jiei-S/boilerplate-clean-architecture/internal/infrastructure/openapi"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRequestID(t *testing.T) {
+	tests := []struct {
+		name   string
+		header string
+		want   string
+	}{
+		{name: "honored", header: "e2e-request-1", want: "e2e-request-1"},
+		{name: "generated if absent", header: ""},
+		{name: "generated if malformed", header: "not a valid id"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, err := http.NewRequest(http.MethodGet, BASE_API_URL+"/00000000-0000-0000-0000-000000000000", nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if tt.header != "" {
+				req.Header.Set("X-Request-ID", tt.header)
+			}
+			resp, err := http.DefaultClient.Do(req)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer resp.Body.Close()
+
+			var problem rest.Problem
+			if err := json.NewDecoder(resp.Body).Decode(&problem); err != nil {
+				t.Fatal(err)
+			}
+			got := resp.Header.Get("X-Request-ID")
+			if tt.want != "" {
+				assert.Equal(t, tt.want, got)
+			} else {
+				assert.NotEmpty(t, got)
+				assert.NotEqual(t, tt.header, got)
+			}
+			if assert.NotNil(t, problem.RequestId) {
+				assert.Equal(t, got, *problem.RequestId)
+			}
+		})
+	}
+}
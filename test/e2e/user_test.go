@@ -3,6 +3,7 @@ package controller_test
 import (
 	"context"
 	"encoding/json"
+	"fmt"
 	"net/http"
 	"strings"
 	"testing"
@@ -11,14 +12,31 @@ import (
 
 	"github.com/Jiei-S/boilerplate-clean-architecture/internal/adapter/gateway"
 	"github.com/Jiei-S/boilerplate-clean-architecture/internal/infrastructure/bun"
+	"github.com/Jiei-S/boilerplate-clean-architecture/internal/infrastructure/config"
 	"github.com/stretchr/testify/assert"
+	uptrace "github.com/uptrace/bun"
 )
 
 const BASE_API_URL = "http://localhost:8081/users"
 
+// newDB connects to the database of the server under test, configured by the
+// same environment.
+func newDB(t *testing.T) *uptrace.DB {
+	cfg, _, err := config.Load(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	db, closeDB, err := bun.NewDB(cfg.Database)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(closeDB)
+	return db
+}
+
 func TestAddUser(t *testing.T) {
 	defer func() {
-		db := bun.NewDB()
+		db := newDB(t)
 		db.NewTruncateTable().Model(&gateway.User{}).Exec(context.Background())
 	}()
 
@@ -52,6 +70,20 @@ func TestAddUser(t *testing.T) {
 			},
 			code: http.StatusBadRequest,
 		},
+		{
+			name: "duplicate",
+			args: args{
+				body: `{"firstName":"test","lastName":"user","age":30}`,
+			},
+			code: http.StatusConflict,
+		},
+		{
+			name: "invalid fields",
+			args: args{
+				body: `{"firstName":" ","lastName":"user","age":-1}`,
+			},
+			code: http.StatusBadRequest,
+		},
 	}
 
 	for _, tt := range tests {
@@ -75,8 +107,45 @@ func TestAddUser(t *testing.T) {
 	}
 }
 
+func TestAddUserIdempotency(t *testing.T) {
+	defer func() {
+		db := newDB(t)
+		db.NewTruncateTable().Model(&gateway.User{}).Exec(context.Background())
+		db.NewTruncateTable().Model(&gateway.IdempotencyKey{}).Exec(context.Background())
+	}()
+
+	post := func(key, body string) (*http.Response, rest.User) {
+		req, err := http.NewRequest(http.MethodPost, BASE_API_URL, strings.NewReader(body))
+		if err != nil {
+			t.Fatal(err)
+		}
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Idempotency-Key", key)
+		r, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer r.Body.Close()
+
+		var act rest.User
+		json.NewDecoder(r.Body).Decode(&act)
+		return r, act
+	}
+
+	first, created := post("retry-1", `{"firstName":"idempotent","lastName":"user","age":20}`)
+	assert.Equal(t, http.StatusOK, first.StatusCode)
+
+	// A retry gets the same user instead of a duplicate.
+	retry, replayed := post("retry-1", `{"firstName":"idempotent","lastName":"user","age":20}`)
+	assert.Equal(t, http.StatusOK, retry.StatusCode)
+	assert.Equal(t, created, replayed)
+
+	reused, _ := post("retry-1", `{"firstName":"idempotent","lastName":"user","age":21}`)
+	assert.Equal(t, http.StatusUnprocessableEntity, reused.StatusCode)
+}
+
 func TestFindUser(t *testing.T) {
-	db := bun.NewDB()
+	db := newDB(t)
 	user := &gateway.User{
 		FirstName: "test",
 		LastName:  "user",
@@ -125,6 +194,85 @@ func TestFindUser(t *testing.T) {
 			}
 			defer r.Body.Close()
 
+			assert.Equal(t, tt.code, r.StatusCode)
+			if tt.code != http.StatusOK {
+				var act rest.Problem
+				if err := json.NewDecoder(r.Body).Decode(&act); err != nil {
+					t.Fatal(err)
+				}
+				assert.Equal(t, "application/problem+json", r.Header.Get("Content-Type"))
+				assert.Equal(t, "USER_NOT_FOUND", act.Reason)
+				return
+			}
+
+			var act rest.User
+			if err := json.NewDecoder(r.Body).Decode(&act); err != nil {
+				t.Fatal(err)
+			}
+			assert.Equal(t, tt.want.FirstName, act.FirstName)
+		})
+	}
+}
+
+func TestUpdateUser(t *testing.T) {
+	db := newDB(t)
+	user := &gateway.User{
+		FirstName: "test",
+		LastName:  "user",
+		Age:       20,
+	}
+	db.NewInsert().Model(user).Exec(context.Background())
+	defer func() {
+		db.NewTruncateTable().Model(&gateway.User{}).Exec(context.Background())
+	}()
+
+	type args struct {
+		id   string
+		body string
+	}
+	tests := []struct {
+		name string
+		args
+		want rest.User
+		code int
+	}{
+		{
+			name: "success",
+			args: args{
+				id:   user.ID,
+				body: `{"firstName":"updated","lastName":"user","age":21}`,
+			},
+			want: rest.User{
+				Id:        user.ID,
+				FirstName: "updated",
+				LastName:  "user",
+				Age:       21,
+			},
+			code: http.StatusOK,
+		},
+		{
+			name: "not found",
+			args: args{
+				id:   "1",
+				body: `{"firstName":"updated","lastName":"user","age":21}`,
+			},
+			code: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, err := http.NewRequest(http.MethodPut, BASE_API_URL+"/"+tt.args.id, strings.NewReader(tt.args.body))
+			if err != nil {
+				t.Fatal(err)
+			}
+			req.Header.Set("Content-Type", "application/json")
+			r, err := http.DefaultClient.Do(req)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer r.Body.Close()
+
 			var act rest.User
 			if err := json.NewDecoder(r.Body).Decode(&act); err != nil {
 				t.Fatal(err)
@@ -132,8 +280,188 @@ func TestFindUser(t *testing.T) {
 
 			assert.Equal(t, tt.code, r.StatusCode)
 			if tt.code == http.StatusOK {
-				assert.Equal(t, tt.want.FirstName, act.FirstName)
+				assert.Equal(t, tt.want, act)
 			}
 		})
 	}
 }
+
+func TestPatchUser(t *testing.T) {
+	db := newDB(t)
+	user := &gateway.User{
+		FirstName: "test",
+		LastName:  "user",
+		Age:       20,
+	}
+	db.NewInsert().Model(user).Exec(context.Background())
+	defer func() {
+		db.NewTruncateTable().Model(&gateway.User{}).Exec(context.Background())
+	}()
+
+	type args struct {
+		id   string
+		body string
+	}
+	tests := []struct {
+		name string
+		args
+		want rest.User
+		code int
+	}{
+		{
+			name: "success",
+			args: args{
+				id:   user.ID,
+				body: `{"age":30}`,
+			},
+			want: rest.User{
+				Id:        user.ID,
+				FirstName: "test",
+				LastName:  "user",
+				Age:       30,
+			},
+			code: http.StatusOK,
+		},
+		{
+			name: "not found",
+			args: args{
+				id:   "1",
+				body: `{"age":30}`,
+			},
+			code: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, err := http.NewRequest(http.MethodPatch, BASE_API_URL+"/"+tt.args.id, strings.NewReader(tt.args.body))
+			if err != nil {
+				t.Fatal(err)
+			}
+			req.Header.Set("Content-Type", "application/json")
+			r, err := http.DefaultClient.Do(req)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer r.Body.Close()
+
+			var act rest.User
+			if err := json.NewDecoder(r.Body).Decode(&act); err != nil {
+				t.Fatal(err)
+			}
+
+			assert.Equal(t, tt.code, r.StatusCode)
+			if tt.code == http.StatusOK {
+				assert.Equal(t, tt.want, act)
+			}
+		})
+	}
+}
+
+func TestDeleteUser(t *testing.T) {
+	db := newDB(t)
+	user := &gateway.User{
+		FirstName: "test",
+		LastName:  "user",
+		Age:       20,
+	}
+	db.NewInsert().Model(user).Exec(context.Background())
+	defer func() {
+		db.NewTruncateTable().Model(&gateway.User{}).Exec(context.Background())
+	}()
+
+	type args struct {
+		id string
+	}
+	tests := []struct {
+		name string
+		args
+		code int
+	}{
+		{
+			name: "success",
+			args: args{
+				id: user.ID,
+			},
+			code: http.StatusNoContent,
+		},
+		{
+			name: "already deleted",
+			args: args{
+				id: user.ID,
+			},
+			code: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, err := http.NewRequest(http.MethodDelete, BASE_API_URL+"/"+tt.args.id, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			r, err := http.DefaultClient.Do(req)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer r.Body.Close()
+
+			assert.Equal(t, tt.code, r.StatusCode)
+		})
+	}
+}
+
+func TestListUsers(t *testing.T) {
+	db := newDB(t)
+	for i, age := range []int32{20, 30, 40} {
+		db.NewInsert().Model(&gateway.User{
+			FirstName: fmt.Sprintf("test%d", i),
+			LastName:  "user",
+			Age:       age,
+		}).Exec(context.Background())
+	}
+	defer func() {
+		db.NewTruncateTable().Model(&gateway.User{}).Exec(context.Background())
+	}()
+
+	list := func(t *testing.T, query string) (rest.UserList, int) {
+		r, err := http.Get(BASE_API_URL + "?" + query)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer r.Body.Close()
+
+		var act rest.UserList
+		if err := json.NewDecoder(r.Body).Decode(&act); err != nil {
+			t.Fatal(err)
+		}
+		return act, r.StatusCode
+	}
+
+	t.Run("paginate", func(t *testing.T) {
+		first, code := list(t, "sort=age&limit=2")
+		assert.Equal(t, http.StatusOK, code)
+		assert.Len(t, first.Items, 2)
+		assert.Equal(t, 20, first.Items[0].Age)
+		assert.NotNil(t, first.NextCursor)
+
+		second, code := list(t, "sort=age&limit=2&cursor="+*first.NextCursor)
+		assert.Equal(t, http.StatusOK, code)
+		assert.Len(t, second.Items, 1)
+		assert.Equal(t, 40, second.Items[0].Age)
+		assert.Nil(t, second.NextCursor)
+	})
+
+	t.Run("filter", func(t *testing.T) {
+		act, code := list(t, "minAge=25&maxAge=35")
+		assert.Equal(t, http.StatusOK, code)
+		assert.Len(t, act.Items, 1)
+		assert.Equal(t, 30, act.Items[0].Age)
+	})
+
+	t.Run("cursor of another sort", func(t *testing.T) {
+		first, _ := list(t, "sort=age&limit=1")
+		_, code := list(t, "sort=-age&cursor="+*first.NextCursor)
+		assert.Equal(t, http.StatusBadRequest, code)
+	})
+}